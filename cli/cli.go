@@ -7,6 +7,11 @@ import (
 	"strings"
 )
 
+// Warn prints a warning about something that doesn't stop the command
+func Warn(format string, a ...any) {
+	_, _ = fmt.Fprintf(os.Stderr, "otis: (warning) "+format+"\n", a...)
+}
+
 func Confirm(prompt string) bool {
 	reader := bufio.NewReader(os.Stdin)
 	fmt.Printf("%s [Y/n]: ", prompt)
@@ -0,0 +1,39 @@
+package apply
+
+import (
+	"gwcoffey/otis/ms"
+	"gwcoffey/otis/msfs"
+	"gwcoffey/otis/work"
+	"path/filepath"
+)
+
+type Args struct {
+	Plan  string `arg:"positional,required" help:"a plan saved with --plan-json"`
+	Force bool   `arg:"--force,-f" help:"make the changes without confirmation"`
+}
+
+func Apply(args *Args) (err error) {
+	workList, err := work.ReadPlan(args.Plan)
+	if err != nil || len(workList) == 0 {
+		return
+	}
+
+	// the plan's paths are absolute, so it belongs to the project that holds them, wherever the
+	// plan itself is kept (the first item's folder always exists before the plan runs)
+	manuscript, err := ms.LoadContaining(filepath.Dir(workList[0].Path()))
+	if err != nil {
+		return
+	}
+
+	journal, err := msfs.Journal(manuscript.Path())
+	if err != nil {
+		return
+	}
+
+	err = work.Execute(workList, args.Force, journal)
+	if err != nil {
+		return
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package apply
+
+import (
+	"gwcoffey/otis/commands/chapter"
+	"gwcoffey/otis/commands/join"
+	"gwcoffey/otis/commands/mkdir"
+	"gwcoffey/otis/commands/mv"
+	"gwcoffey/otis/commands/renumber"
+	"gwcoffey/otis/commands/rm"
+	"gwcoffey/otis/commands/split"
+	"gwcoffey/otis/commands/touch"
+	"gwcoffey/otis/fixture"
+	"gwcoffey/otis/msfs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// command runs a structural command on the project at root; force, dryRun, and planJson are
+// passed along as the command's flags
+type command func(root string, force bool, dryRun bool, planJson bool) error
+
+var commands = map[string]command{
+	"touch": func(root string, force bool, dryRun bool, planJson bool) error {
+		at := 0
+		path := filepath.Join(root, "manuscript", "00-beginning")
+		return touch.Touch(&touch.Args{Path: path, Name: "Dawn", At: &at, Force: force, DryRun: dryRun, PlanJson: planJson})
+	},
+	"mkdir": func(root string, force bool, dryRun bool, planJson bool) error {
+		at := 1
+		path := filepath.Join(root, "manuscript")
+		return mkdir.MkDir(&mkdir.Args{Path: path, Name: "Interlude", At: &at, Force: force, DryRun: dryRun, PlanJson: planJson})
+	},
+	"mv": func(root string, force bool, dryRun bool, planJson bool) error {
+		at := 0
+		path := filepath.Join(root, "manuscript", "00-beginning", "02-calm.md")
+		return mv.Mv(&mv.Args{Path: path, At: &at, Force: force, DryRun: dryRun, PlanJson: planJson})
+	},
+	"rm": func(root string, force bool, dryRun bool, planJson bool) error {
+		path := filepath.Join(root, "manuscript", "00-beginning", "00-arrival.md")
+		return rm.Rm(&rm.Args{Path: path, Force: force, DryRun: dryRun, PlanJson: planJson})
+	},
+	"split": func(root string, force bool, dryRun bool, planJson bool) error {
+		path := filepath.Join(root, "manuscript", "00-beginning", "01-storm.md")
+		return split.Split(&split.Args{Path: path, AllMarkers: true, Force: force, DryRun: dryRun, PlanJson: planJson})
+	},
+	"join": func(root string, force bool, dryRun bool, planJson bool) error {
+		folder := filepath.Join(root, "manuscript", "00-beginning")
+		return join.Join(&join.Args{Folder: &folder, Force: force, DryRun: dryRun, PlanJson: planJson})
+	},
+	"renumber": func(root string, force bool, dryRun bool, planJson bool) error {
+		width := 3
+		path := filepath.Join(root, "manuscript")
+		return renumber.Renumber(&renumber.Args{Path: &path, Width: &width, Force: force, DryRun: dryRun, PlanJson: planJson})
+	},
+	"chapter add": func(root string, force bool, dryRun bool, planJson bool) error {
+		folder := filepath.Join(root, "manuscript", "02-end")
+		return chapter.Chapter(&chapter.Args{Add: &chapter.AddArgs{Folder: folder, Title: "End", Force: force, DryRun: dryRun, PlanJson: planJson}})
+	},
+	"chapter mv": func(root string, force bool, dryRun bool, planJson bool) error {
+		from, to := filepath.Join(root, "manuscript", "01-middle"), filepath.Join(root, "manuscript", "02-end")
+		return chapter.Chapter(&chapter.Args{Mv: &chapter.MvArgs{From: from, To: to, Force: force, DryRun: dryRun, PlanJson: planJson}})
+	},
+}
+
+func journalLength(t *testing.T, root string) int {
+	journal, err := msfs.Journal(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, err := journal.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return len(entries)
+}
+
+// TestPreviewAndApply checks that --dry-run and --plan-json change nothing, and that applying the
+// plan (from outside the project) changes the manuscript the same way running the command does
+func TestPreviewAndApply(t *testing.T) {
+	original := fixture.Files(t, fixture.Path("scenes"))
+
+	for name, run := range commands {
+		t.Run(name, func(t *testing.T) {
+			// what the command does when it runs
+			direct := fixture.Project(t, "scenes")
+			if err := run(direct, true, false, false); err != nil {
+				t.Fatal(err)
+			}
+			expected := fixture.Files(t, filepath.Join(direct, "manuscript"))
+
+			root := fixture.Project(t, "scenes")
+			var err error
+			preview := fixture.Stdout(t, func() { err = run(root, false, true, false) })
+			if err != nil || strings.TrimSpace(preview) == "" {
+				t.Errorf("--dry-run: expected a preview, got %q %v", preview, err)
+			}
+			plan := fixture.Stdout(t, func() { err = run(root, false, false, true) })
+			if err != nil || !strings.HasPrefix(plan, "[") {
+				t.Errorf("--plan-json: expected a plan, got %q %v", plan, err)
+			}
+			if files := fixture.Files(t, root); !slices.Equal(files, original) {
+				t.Errorf("expected the previews to change nothing, got %v", files)
+			}
+
+			// apply the plan from another project
+			planFile := filepath.Join(t.TempDir(), "plan.json")
+			if err = os.WriteFile(planFile, []byte(plan), 0644); err != nil {
+				t.Fatal(err)
+			}
+			elsewhere := fixture.Project(t, "scenes")
+			fixture.Chdir(t, elsewhere)
+			if err = Apply(&Args{Plan: planFile, Force: true}); err != nil {
+				t.Fatal(err)
+			}
+			if files := fixture.Files(t, filepath.Join(root, "manuscript")); !slices.Equal(files, expected) {
+				t.Errorf("expected the plan to make %v, got %v", expected, files)
+			}
+			if journalLength(t, root) != 1 || journalLength(t, elsewhere) != 0 {
+				t.Error("expected the plan to be journaled in its own project")
+			}
+		})
+	}
+}
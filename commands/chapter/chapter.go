@@ -0,0 +1,211 @@
+package chapter
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-yaml/yaml"
+	"gwcoffey/otis/ms"
+	"gwcoffey/otis/msfs"
+	"gwcoffey/otis/oerr"
+	"gwcoffey/otis/work"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+type AddArgs struct {
+	Folder     string `arg:"positional,required" help:"the folder where the chapter starts"`
+	Title      string `arg:"positional,required" help:"the chapter title"`
+	Unnumbered bool   `arg:"--unnumbered,-u" help:"leave the chapter out of the chapter numbering"`
+	Force      bool   `arg:"--force,-f" help:"make the change without confirmation"`
+	DryRun     bool   `arg:"--dry-run,-n" help:"show what would change without changing anything"`
+	PlanJson   bool   `arg:"--plan-json" help:"print what would change as JSON (for otis apply) without changing anything"`
+}
+
+type RmArgs struct {
+	Folder   string `arg:"positional,required" help:"the folder where the chapter starts"`
+	Force    bool   `arg:"--force,-f" help:"make the change without confirmation"`
+	DryRun   bool   `arg:"--dry-run,-n" help:"show what would change without changing anything"`
+	PlanJson bool   `arg:"--plan-json" help:"print what would change as JSON (for otis apply) without changing anything"`
+}
+
+type MvArgs struct {
+	From     string `arg:"positional,required" help:"the folder where the chapter starts now"`
+	To       string `arg:"positional,required" help:"the folder where the chapter should start"`
+	Force    bool   `arg:"--force,-f" help:"make the change without confirmation"`
+	DryRun   bool   `arg:"--dry-run,-n" help:"show what would change without changing anything"`
+	PlanJson bool   `arg:"--plan-json" help:"print what would change as JSON (for otis apply) without changing anything"`
+}
+
+type LsArgs struct {
+	ProjectPath *string `arg:"positional" help:"path to the otis project"`
+}
+
+type Args struct {
+	Add *AddArgs `arg:"subcommand:add" help:"start a chapter at a folder"`
+	Rm  *RmArgs  `arg:"subcommand:rm" help:"remove a chapter (its scenes join the chapter before)"`
+	Mv  *MvArgs  `arg:"subcommand:mv" help:"start a chapter at a different folder"`
+	Ls  *LsArgs  `arg:"subcommand:ls" help:"list the chapters and their folders"`
+}
+
+// chapterFile is the content of a new chapter.yml
+type chapterFile struct {
+	Title    string `yaml:"title"`
+	Numbered *bool  `yaml:"numbered,omitempty"`
+}
+
+const metaFilename = "chapter.yml"
+
+// chapterFolders returns the folders of the manuscript's chapters
+func chapterFolders(m ms.Manuscript) (folders []string) {
+	for _, chapter := range m.Whole().Chapters() {
+		folders = append(folders, chapter.Path())
+	}
+	return
+}
+
+// loadFolder loads the manuscript containing a folder and returns the folder's absolute path,
+// and whether it starts a chapter
+func loadFolder(folder string) (m ms.Manuscript, path string, isChapter bool, err error) {
+	info, err := os.Stat(folder)
+	if err != nil {
+		return
+	}
+	if !info.IsDir() {
+		return nil, "", false, oerr.FolderNotFound(folder)
+	}
+	if m, err = ms.LoadContaining(folder); err != nil {
+		return
+	}
+	if path, err = filepath.Abs(folder); err != nil {
+		return
+	}
+	_, err = os.Stat(filepath.Join(path, metaFilename))
+	if errors.Is(err, fs.ErrNotExist) {
+		return m, path, false, nil
+	}
+	return m, path, err == nil, err
+}
+
+// execute checks that the manuscript would still be laid out properly (starting with a chapter,
+// and with the first chapter starting the first part), and then makes the change
+func execute(m ms.Manuscript, folders []string, workList work.List, force bool, dryRun bool, planJson bool) (err error) {
+	if err = ms.CheckChapterFolders(m, folders); err != nil {
+		return oerr.InvalidPlan(err.Error())
+	}
+
+	if dryRun || planJson {
+		return work.Preview(workList, planJson, os.Stdout)
+	}
+
+	journal, err := msfs.Journal(m.Path())
+	if err != nil {
+		return
+	}
+
+	return work.Execute(workList, force, journal)
+}
+
+func add(args *AddArgs) (err error) {
+	m, folder, isChapter, err := loadFolder(args.Folder)
+	if err != nil {
+		return
+	}
+	if isChapter {
+		return oerr.AlreadyAChapter(args.Folder)
+	}
+
+	meta := chapterFile{Title: args.Title}
+	if args.Unnumbered {
+		numbered := false
+		meta.Numbered = &numbered
+	}
+	content, err := yaml.Marshal(meta)
+	if err != nil {
+		return
+	}
+
+	workList := work.AddFileWithContent(work.List{}, filepath.Join(args.Folder, metaFilename), string(content))
+	return execute(m, append(chapterFolders(m), folder), workList, args.Force, args.DryRun, args.PlanJson)
+}
+
+func rm(args *RmArgs) (err error) {
+	m, folder, isChapter, err := loadFolder(args.Folder)
+	if err != nil {
+		return
+	}
+	if !isChapter {
+		return oerr.NotAChapter(args.Folder)
+	}
+
+	path := filepath.Join(args.Folder, metaFilename)
+	keep, err := msfs.TrashPath(m.Path(), path)
+	if err != nil {
+		return
+	}
+
+	workList := work.AppendRemove(work.List{}, path, keep)
+	folders := slices.DeleteFunc(chapterFolders(m), func(f string) bool { return f == folder })
+	return execute(m, folders, workList, args.Force, args.DryRun, args.PlanJson)
+}
+
+func mv(args *MvArgs) (err error) {
+	m, from, isChapter, err := loadFolder(args.From)
+	if err != nil {
+		return
+	}
+	if !isChapter {
+		return oerr.NotAChapter(args.From)
+	}
+	_, to, isChapter, err := loadFolder(args.To)
+	if err != nil {
+		return
+	}
+	if isChapter {
+		return oerr.AlreadyAChapter(args.To)
+	}
+
+	workList := work.AppendMove(work.List{}, filepath.Join(args.From, metaFilename), filepath.Join(args.To, metaFilename))
+	folders := slices.DeleteFunc(chapterFolders(m), func(f string) bool { return f == from })
+	return execute(m, append(folders, to), workList, args.Force, args.DryRun, args.PlanJson)
+}
+
+func ls(args *LsArgs) (err error) {
+	var m ms.Manuscript
+	if args.ProjectPath == nil {
+		m, err = ms.LoadHere()
+	} else {
+		m, err = ms.Load(*args.ProjectPath)
+	}
+	if err != nil {
+		return
+	}
+
+	for _, chapter := range m.Chapters() {
+		number := "-"
+		if chapter.Number() != nil {
+			number = fmt.Sprintf("%d", *chapter.Number())
+		}
+		folder, rerr := filepath.Rel(m.Path(), chapter.Path())
+		if rerr != nil {
+			folder = chapter.Path()
+		}
+		fmt.Printf("%4s  %-40s %s\n", number, chapter.Title(), folder)
+	}
+	return nil
+}
+
+func Chapter(args *Args) error {
+	switch {
+	case args.Add != nil:
+		return add(args.Add)
+	case args.Rm != nil:
+		return rm(args.Rm)
+	case args.Mv != nil:
+		return mv(args.Mv)
+	case args.Ls != nil:
+		return ls(args.Ls)
+	}
+	return oerr.ChapterSubcommandRequired()
+}
@@ -0,0 +1,100 @@
+package chapter
+
+import (
+	"gwcoffey/otis/fixture"
+	"gwcoffey/otis/msfs"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestAdd(t *testing.T) {
+	root := fixture.Project(t, "scenes")
+	end := filepath.Join(root, "manuscript", "02-end")
+
+	if err := Chapter(&Args{Add: &AddArgs{Folder: end, Title: "The End", Unnumbered: true, Force: true}}); err != nil {
+		t.Fatal(err)
+	}
+	if text := fixture.Read(t, filepath.Join(end, "chapter.yml")); text != "title: The End\nnumbered: false\n" {
+		t.Errorf("expected a new chapter.yml, got %q", text)
+	}
+
+	// a folder can only start one chapter, and a scene can't start one at all
+	if Chapter(&Args{Add: &AddArgs{Folder: end, Title: "Again", Force: true}}) == nil {
+		t.Error("expected the folder to already be a chapter")
+	}
+	if Chapter(&Args{Add: &AddArgs{Folder: filepath.Join(end, "00-home.md"), Title: "Home", Force: true}}) == nil {
+		t.Error("expected a scene not to be a folder")
+	}
+}
+
+func TestRm(t *testing.T) {
+	root := fixture.Project(t, "scenes")
+	manuscript := filepath.Join(root, "manuscript")
+
+	// the manuscript must still start with a chapter
+	if Chapter(&Args{Rm: &RmArgs{Folder: filepath.Join(manuscript, "00-beginning"), Force: true}}) == nil {
+		t.Error("expected the first chapter to stay")
+	}
+	if Chapter(&Args{Rm: &RmArgs{Folder: filepath.Join(manuscript, "02-end"), Force: true}}) == nil {
+		t.Error("expected a folder without a chapter to fail")
+	}
+	expected := []string{"00-arrival.md", "01-storm.md", "02-calm.md", "chapter.yml"}
+	if files := fixture.Files(t, filepath.Join(manuscript, "00-beginning")); !slices.Equal(files, expected) {
+		t.Errorf("expected the first chapter to be left alone, got %v", files)
+	}
+
+	if err := Chapter(&Args{Rm: &RmArgs{Folder: filepath.Join(manuscript, "01-middle"), Force: true}}); err != nil {
+		t.Fatal(err)
+	}
+	if files := fixture.Files(t, filepath.Join(manuscript, "01-middle")); !slices.Equal(files, []string{"00-road.md", "01-city.md"}) {
+		t.Errorf("expected chapter.yml to be gone, got %v", files)
+	}
+
+	journal, err := msfs.Journal(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entries, err := journal.Entries(); err != nil || len(entries) != 1 {
+		t.Errorf("expected only the removal that worked in the journal, got %v %v", entries, err)
+	}
+}
+
+func TestMv(t *testing.T) {
+	root := fixture.Project(t, "scenes")
+	manuscript := filepath.Join(root, "manuscript")
+
+	from, to := filepath.Join(manuscript, "01-middle"), filepath.Join(manuscript, "02-end")
+	if err := Chapter(&Args{Mv: &MvArgs{From: from, To: to, Force: true}}); err != nil {
+		t.Fatal(err)
+	}
+	if text := fixture.Read(t, filepath.Join(to, "chapter.yml")); text != "title: Middle\n" {
+		t.Errorf("expected the chapter to move, got %q", text)
+	}
+
+	// moving the first chapter later would leave scenes before it
+	if Chapter(&Args{Mv: &MvArgs{From: filepath.Join(manuscript, "00-beginning"), To: from, Force: true}}) == nil {
+		t.Error("expected the first chapter to stay")
+	}
+}
+
+func TestLs(t *testing.T) {
+	root := fixture.Project(t, "scenes")
+
+	out := fixture.Stdout(t, func() {
+		if err := Chapter(&Args{Ls: &LsArgs{ProjectPath: &root}}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 || strings.Fields(lines[0])[0] != "1" || !strings.HasSuffix(lines[1], "manuscript/01-middle") {
+		t.Errorf("expected two chapters, got %q", out)
+	}
+}
+
+func TestSubcommandRequired(t *testing.T) {
+	if Chapter(&Args{}) == nil {
+		t.Error("expected an error without a subcommand")
+	}
+}
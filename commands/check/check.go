@@ -0,0 +1,59 @@
+package check
+
+import (
+	"encoding/json"
+	"fmt"
+	"gwcoffey/otis/ms"
+	"gwcoffey/otis/oerr"
+	"os"
+)
+
+type Args struct {
+	ProjectPath *string `arg:"positional" help:"path to the otis project"`
+	Json        bool    `arg:"--json" help:"print the problems as JSON"`
+	Strict      bool    `arg:"--strict" help:"fail on warnings as well as errors"`
+}
+
+func Check(args *Args) (err error) {
+	path := "."
+	if args.ProjectPath != nil {
+		path = *args.ProjectPath
+	}
+
+	problems, err := ms.Check(path)
+	if err != nil {
+		return
+	}
+
+	if args.Json {
+		if problems == nil {
+			problems = []ms.Problem{}
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err = encoder.Encode(problems); err != nil {
+			return
+		}
+	} else if len(problems) == 0 {
+		fmt.Println("no problems found")
+	}
+
+	failures := 0
+	for _, problem := range problems {
+		if !args.Json {
+			color := "\033[93m"
+			if problem.Severity == ms.SeverityError {
+				color = "\033[91m"
+			}
+			fmt.Printf("%s%-7s\033[0m %s: %s\n", color, problem.Severity, problem.Path, problem.Message)
+		}
+		if problem.Severity == ms.SeverityError || args.Strict {
+			failures++
+		}
+	}
+
+	if failures > 0 {
+		return oerr.ManuscriptHasProblems(failures)
+	}
+	return nil
+}
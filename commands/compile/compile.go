@@ -5,14 +5,20 @@ import (
 	_ "embed"
 	"fmt"
 	ms2 "gwcoffey/otis/ms"
+	"gwcoffey/otis/ms/compile"
+	"gwcoffey/otis/ms/compile/docx"
+	"gwcoffey/otis/ms/compile/epub"
 	"gwcoffey/otis/ms/compile/html"
+	"gwcoffey/otis/ms/compile/pdf"
 	"gwcoffey/otis/ms/compile/rtf"
 	"gwcoffey/otis/ms/compile/tex"
 	"gwcoffey/otis/msfs"
+	"gwcoffey/otis/oerr"
 	"gwcoffey/otis/text"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -20,12 +26,20 @@ import (
 type Format int
 
 type Args struct {
-	ProjectPath *string `arg:"positional"`
-	Format      string  `arg:"-f" help:"the compiled output format (PDF, RTF, HTML, or TEX)" default:"PDF"`
-	Tag         *string `arg:"-t" help:"tag to append to the filename, [default: <current date>]"`
+	ProjectPath   *string  `arg:"positional"`
+	Profile       *string  `arg:"-p" help:"a compile profile from otis.yml to use"`
+	Format        *string  `arg:"-f" help:"the compiled output format (PDF, RTF, DOCX, HTML, EPUB, or TEX) [default: PDF]"`
+	Tag           *string  `arg:"-t" help:"tag to append to the filename, [default: <current date>]"`
+	Engine        string   `arg:"-e" help:"how to produce PDF output (NATIVE, or LATEX to use pdflatex)" default:"NATIVE"`
+	Chapters      *string  `arg:"--chapters" help:"compile only these chapters, like 3, 3-7, or 1,4-6"`
+	Folders       []string `arg:"--folder,separate" help:"compile only the scenes in this folder (can be repeated)"`
+	Scenes        *string  `arg:"--scenes" help:"compile only these scenes, numbered from the start of the manuscript, like 1-5"`
+	Where         []string `arg:"--where,separate" help:"compile only scenes whose metadata matches, like pov=Anna (can be repeated)"`
+	ExcludeStatus []string `arg:"--exclude-status,separate" help:"leave out scenes with this status (can be repeated)"`
+	WordCount     *string  `arg:"--word-count" help:"word count on the title page: WHOLE manuscript or just the EXCERPT [default: WHOLE]"`
 }
 
-func generateTex(fileName string, manuscript ms2.Manuscript) (err error) {
+func generateTex(fileName string, manuscript ms2.Manuscript, opts compile.Options) (err error) {
 	outDir, err := msfs.DistDir(manuscript.Path())
 	if err != nil {
 		return
@@ -37,10 +51,31 @@ func generateTex(fileName string, manuscript ms2.Manuscript) (err error) {
 		return
 	}
 
-	return writeTex(path, manuscript)
+	return writeTex(path, manuscript, opts)
 }
 
-func generatePdf(fileName string, manuscript ms2.Manuscript) (err error) {
+func generatePdf(fileName string, manuscript ms2.Manuscript, opts compile.Options) (err error) {
+	outDir, err := msfs.DistDir(manuscript.Path())
+	if err != nil {
+		return
+	}
+
+	path := filepath.Join(outDir, fileName+".pdf")
+	err = os.MkdirAll(filepath.Dir(path), os.ModePerm)
+	if err != nil {
+		return
+	}
+
+	pdfContent, err := pdf.ManuscriptToPdf(manuscript, opts)
+	if err != nil {
+		return
+	}
+
+	err = os.WriteFile(path, pdfContent, 0644)
+	return
+}
+
+func generateLatexPdf(fileName string, manuscript ms2.Manuscript, opts compile.Options) (err error) {
 	tmpDir, err := msfs.TmpDir(manuscript.Path())
 	if err != nil {
 		return
@@ -69,7 +104,7 @@ func generatePdf(fileName string, manuscript ms2.Manuscript) (err error) {
 		return
 	}
 
-	err = writeTex(texPath, manuscript)
+	err = writeTex(texPath, manuscript, opts)
 	if err != nil {
 		return
 	}
@@ -83,7 +118,7 @@ func generatePdf(fileName string, manuscript ms2.Manuscript) (err error) {
 	return
 }
 
-func generateHtml(fileName string, manuscript ms2.Manuscript) (err error) {
+func generateHtml(fileName string, manuscript ms2.Manuscript, opts compile.Options) (err error) {
 	outDir, err := msfs.DistDir(manuscript.Path())
 	if err != nil {
 		return
@@ -107,7 +142,7 @@ func generateHtml(fileName string, manuscript ms2.Manuscript) (err error) {
 		}
 	}()
 
-	htmlContent, err := html.ManuscriptToHtml(manuscript)
+	htmlContent, err := html.ManuscriptToHtml(manuscript, opts)
 	if err != nil {
 		return
 	}
@@ -120,7 +155,7 @@ func generateHtml(fileName string, manuscript ms2.Manuscript) (err error) {
 	return
 }
 
-func generateRtf(fileName string, manuscript ms2.Manuscript) (err error) {
+func generateRtf(fileName string, manuscript ms2.Manuscript, opts compile.Options) (err error) {
 	outDir, err := msfs.DistDir(manuscript.Path())
 	if err != nil {
 		return
@@ -144,7 +179,7 @@ func generateRtf(fileName string, manuscript ms2.Manuscript) (err error) {
 		}
 	}()
 
-	rtfContent, err := rtf.ManuscriptToHtml(manuscript)
+	rtfContent, err := rtf.ManuscriptToHtml(manuscript, opts)
 	if err != nil {
 		return
 	}
@@ -157,6 +192,48 @@ func generateRtf(fileName string, manuscript ms2.Manuscript) (err error) {
 	return
 }
 
+func generateDocx(fileName string, manuscript ms2.Manuscript, opts compile.Options) (err error) {
+	outDir, err := msfs.DistDir(manuscript.Path())
+	if err != nil {
+		return
+	}
+
+	path := filepath.Join(outDir, fileName+".docx")
+	err = os.MkdirAll(filepath.Dir(path), os.ModePerm)
+	if err != nil {
+		return
+	}
+
+	docxContent, err := docx.ManuscriptToDocx(manuscript, opts)
+	if err != nil {
+		return
+	}
+
+	err = os.WriteFile(path, docxContent, 0644)
+	return
+}
+
+func generateEpub(fileName string, manuscript ms2.Manuscript, opts compile.Options) (err error) {
+	outDir, err := msfs.DistDir(manuscript.Path())
+	if err != nil {
+		return
+	}
+
+	path := filepath.Join(outDir, fileName+".epub")
+	err = os.MkdirAll(filepath.Dir(path), os.ModePerm)
+	if err != nil {
+		return
+	}
+
+	epubContent, err := epub.ManuscriptToEpub(manuscript, opts)
+	if err != nil {
+		return
+	}
+
+	err = os.WriteFile(path, epubContent, 0644)
+	return
+}
+
 func execPdfLatex(texPath string, manuscript ms2.Manuscript) (err error) {
 	tmpDir, err := msfs.TmpDir(manuscript.Path())
 	if err != nil {
@@ -176,7 +253,7 @@ func execPdfLatex(texPath string, manuscript ms2.Manuscript) (err error) {
 	return
 }
 
-func writeTex(path string, manuscript ms2.Manuscript) (err error) {
+func writeTex(path string, manuscript ms2.Manuscript, opts compile.Options) (err error) {
 	file, err := os.Create(path)
 	if err != nil {
 		return
@@ -189,7 +266,7 @@ func writeTex(path string, manuscript ms2.Manuscript) (err error) {
 		}
 	}()
 
-	latex, err := tex.ManuscriptToTex(manuscript)
+	latex, err := tex.ManuscriptToTex(manuscript, opts)
 	if err != nil {
 		return
 	}
@@ -202,10 +279,120 @@ func writeTex(path string, manuscript ms2.Manuscript) (err error) {
 	return
 }
 
+// parseRange parses a selection like "3", "3-7" or "1,4-6" into the zero-based indexes of the
+// selected items, out of count items numbered from 1
+func parseRange(option string, selection string, count int) (indexes []int, err error) {
+	for _, part := range strings.Split(selection, ",") {
+		first, last, isRange := strings.Cut(strings.TrimSpace(part), "-")
+		start, serr := strconv.Atoi(first)
+		end := start
+		var eerr error
+		if isRange {
+			end, eerr = strconv.Atoi(last)
+		}
+		if serr != nil || eerr != nil || start < 1 || end < start || end > count {
+			return nil, oerr.InvalidCompileOption(option, selection)
+		}
+		for i := start; i <= end; i++ {
+			indexes = append(indexes, i-1)
+		}
+	}
+	return
+}
+
+// selectScenes narrows the manuscript to the given chapters, folders, and scenes. Chapters and
+// scenes are numbered through the whole manuscript, so a selection means the same thing no
+// matter what other filters apply.
+func selectScenes(m ms2.Manuscript, chapters *string, folders []string, scenes *string) (ms2.Manuscript, error) {
+	if chapters != nil {
+		all := m.Whole().Chapters()
+		indexes, err := parseRange("chapters", *chapters, len(all))
+		if err != nil {
+			return nil, err
+		}
+		var selected []ms2.Chapter
+		for _, i := range indexes {
+			selected = append(selected, all[i])
+		}
+		m = m.Filter(ms2.InChapters(selected))
+	}
+
+	if len(folders) > 0 {
+		var selected []ms2.Folder
+		for _, path := range folders {
+			folder, err := ms2.FindFolder(m.Whole(), path)
+			if err != nil {
+				return nil, err
+			}
+			selected = append(selected, folder)
+		}
+		m = m.Filter(ms2.InFolders(selected))
+	}
+
+	if scenes != nil {
+		all := m.Whole().Scenes()
+		indexes, err := parseRange("scenes", *scenes, len(all))
+		if err != nil {
+			return nil, err
+		}
+		var selected []ms2.Scene
+		for _, i := range indexes {
+			selected = append(selected, all[i])
+		}
+		m = m.Filter(ms2.InScenes(selected))
+	}
+
+	return m, nil
+}
+
+// applyProfile narrows the manuscript and sets the options according to the profile
+func applyProfile(m ms2.Manuscript, profile ms2.Profile, opts *compile.Options) (ms2.Manuscript, error) {
+	if profile.SceneBreak() != nil {
+		opts.SceneBreak = *profile.SceneBreak()
+	}
+	if profile.ChapterHeading() != nil {
+		style, err := compile.ParseHeadingStyle(*profile.ChapterHeading())
+		if err != nil {
+			return nil, err
+		}
+		opts.ChapterHeading = style
+	}
+	if profile.TitlePage() != nil {
+		opts.TitlePage = *profile.TitlePage()
+	}
+	if profile.WordCount() != nil {
+		scope, err := compile.ParseWordCountScope(*profile.WordCount())
+		if err != nil {
+			return nil, err
+		}
+		opts.WordCount = scope
+	}
+
+	// an excerpt is just the selected chapters, without the front and back matter
+	if profile.Chapters() != nil || len(profile.Folders()) > 0 {
+		opts.Sections = false
+	}
+
+	// profile folders are relative to the project root
+	var folders []string
+	for _, path := range profile.Folders() {
+		folders = append(folders, filepath.Join(m.Path(), path))
+	}
+	return selectScenes(m, profile.Chapters(), folders, nil)
+}
+
+// expandTag fills in the {date} and {profile} placeholders in a profile's tag template
+func expandTag(template string, profile ms2.Profile) string {
+	return strings.NewReplacer(
+		"{date}", time.Now().Format("2006-01-02"),
+		"{profile}", profile.Name(),
+	).Replace(template)
+}
+
 func Compile(args *Args) (err error) {
 	var manuscript ms2.Manuscript
 
-	if &args.ProjectPath == nil {
+	if args.ProjectPath == nil {
 		manuscript, err = ms2.LoadHere()
 	} else {
 		manuscript, err = ms2.Load(*args.ProjectPath)
@@ -214,22 +401,79 @@ func Compile(args *Args) (err error) {
 		return
 	}
 
-	var fileName string
+	// scenes excluded in their front matter are never compiled
+	manuscript = manuscript.Filter(ms2.NotExcluded)
+
+	// otis.yml sets the chapter heading for the project, and a profile can override it
+	opts := compile.DefaultOptions()
+	opts.ChapterHeading = manuscript.ChapterHeading()
+	format := "PDF"
+	tag := time.Now().Format("2006-01-02")
+
+	if args.Profile != nil {
+		var profile ms2.Profile
+		profile, err = manuscript.Profile(*args.Profile)
+		if err != nil {
+			return
+		}
+		manuscript, err = applyProfile(manuscript, profile, &opts)
+		if err != nil {
+			return
+		}
+		if profile.Format() != nil {
+			format = *profile.Format()
+		}
+		if profile.Tag() != nil {
+			tag = expandTag(*profile.Tag(), profile)
+		}
+	}
+
+	// command line arguments override the profile
+	if args.Format != nil {
+		format = *args.Format
+	}
 	if args.Tag != nil {
-		fileName = fmt.Sprintf("%s-%s", text.ToKebab(manuscript.Title()), *args.Tag)
-	} else {
-		fileName = fmt.Sprintf("%s-%s", text.ToKebab(manuscript.Title()), time.Now().Format("2006-01-02"))
+		tag = *args.Tag
+	}
+	if args.WordCount != nil {
+		opts.WordCount, err = compile.ParseWordCountScope(*args.WordCount)
+		if err != nil {
+			return
+		}
 	}
+	if args.Chapters != nil || len(args.Folders) > 0 || args.Scenes != nil {
+		opts.Sections = false
+	}
+	manuscript, err = selectScenes(manuscript, args.Chapters, args.Folders, args.Scenes)
+	if err != nil {
+		return
+	}
+	manuscript, err = ms2.Select(manuscript, args.Where, args.ExcludeStatus)
+	if err != nil {
+		return
+	}
+
+	fileName := fmt.Sprintf("%s-%s", text.ToKebab(manuscript.Title()), tag)
 
-	switch strings.ToUpper(args.Format) {
+	switch strings.ToUpper(format) {
 	case "PDF":
-		err = generatePdf(fileName, manuscript)
+		if strings.ToUpper(args.Engine) == "LATEX" {
+			err = generateLatexPdf(fileName, manuscript, opts)
+		} else {
+			err = generatePdf(fileName, manuscript, opts)
+		}
 	case "RTF":
-		err = generateRtf(fileName, manuscript)
+		err = generateRtf(fileName, manuscript, opts)
+	case "DOCX":
+		err = generateDocx(fileName, manuscript, opts)
 	case "HTML":
-		err = generateHtml(fileName, manuscript)
+		err = generateHtml(fileName, manuscript, opts)
+	case "EPUB":
+		err = generateEpub(fileName, manuscript, opts)
 	case "TEX":
-		err = generateTex(fileName, manuscript)
+		err = generateTex(fileName, manuscript, opts)
+	default:
+		err = oerr.InvalidCompileOption("format", format)
 	}
 	if err != nil {
 		return
@@ -2,9 +2,13 @@ package initcmd
 
 import (
 	_ "embed"
+	"errors"
 	ms2 "gwcoffey/otis/ms"
 	"gwcoffey/otis/oerr"
+	"io/fs"
 	"os"
+	"slices"
+	"strings"
 )
 
 type Args struct {
@@ -22,6 +26,23 @@ var otisTemplate []byte
 //go:embed template/00-scene.md
 var sceneTemplate []byte
 
+// ignoreBuildDir adds otis's build directory (which holds the journal and the trash) to the
+// project's .gitignore, so removed scenes moved into the trash are left for git as deleted
+func ignoreBuildDir() (err error) {
+	content, err := os.ReadFile(".gitignore")
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return
+	}
+	lines := strings.Split(string(content), "\n")
+	if slices.Contains(lines, ".build/") || slices.Contains(lines, ".build") || slices.Contains(lines, "/.build/") {
+		return nil
+	}
+	if len(content) > 0 && !strings.HasSuffix(string(content), "\n") {
+		content = append(content, '\n')
+	}
+	return os.WriteFile(".gitignore", append(content, ".build/\n"...), filePerms)
+}
+
 func Init(args *Args) (err error) {
 	var existing ms2.Manuscript
 
@@ -53,5 +74,10 @@ func Init(args *Args) (err error) {
 		return
 	}
 
+	err = ignoreBuildDir()
+	if err != nil {
+		return
+	}
+
 	return nil
 }
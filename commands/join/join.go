@@ -0,0 +1,139 @@
+package join
+
+import (
+	"gwcoffey/otis/cli"
+	"gwcoffey/otis/ms"
+	"gwcoffey/otis/msfs"
+	"gwcoffey/otis/oerr"
+	"gwcoffey/otis/work"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type Args struct {
+	Scenes   []string `arg:"positional" help:"the scenes to join, into the first one"`
+	Folder   *string  `arg:"--folder" help:"join every scene in this folder into its first scene"`
+	NoBreak  bool     `arg:"--no-break" help:"join the scenes without a scene break (#) between them"`
+	Force    bool     `arg:"--force,-f" help:"move other files around without confirmation"`
+	DryRun   bool     `arg:"--dry-run,-n" help:"show what would change without changing anything"`
+	PlanJson bool     `arg:"--plan-json" help:"print what would change as JSON (for otis apply) without changing anything"`
+}
+
+// folderScenes returns the scene files directly inside a folder
+func folderScenes(dir string) (scenes []string, err error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".md" {
+			scenes = append(scenes, filepath.Join(dir, entry.Name()))
+		}
+	}
+	return
+}
+
+// sortScenes puts the scenes in manuscript order, checking that they are numbered scenes in the
+// same folder
+func sortScenes(scenes []string) (err error) {
+	numbers := map[string]int{}
+	for _, scene := range scenes {
+		if filepath.Dir(scene) != filepath.Dir(scenes[0]) {
+			return oerr.CantJoin("the scenes are not all in the same folder")
+		}
+		if numbers[scene], err = msfs.FileNumber(scene); err != nil {
+			return
+		}
+	}
+	sort.SliceStable(scenes, func(i, j int) bool {
+		return numbers[scenes[i]] < numbers[scenes[j]]
+	})
+	return
+}
+
+// joinContent concatenates the scene files; the result keeps the first scene's front matter and
+// drops the rest
+func joinContent(scenes []string, sceneBreak bool) (string, error) {
+	var out strings.Builder
+	for i, scene := range scenes {
+		content, err := os.ReadFile(scene)
+		if err != nil {
+			return "", err
+		}
+		frontMatter, body := ms.SplitSceneFile(content)
+		if i == 0 {
+			out.Write(frontMatter)
+		} else if sceneBreak {
+			out.WriteString("\n#\n\n")
+		} else {
+			out.WriteString("\n")
+		}
+		out.WriteString(strings.TrimRight(strings.TrimLeft(string(body), "\r\n"), " \t\r\n") + "\n")
+	}
+	return out.String(), nil
+}
+
+func Join(args *Args) (err error) {
+	scenes := args.Scenes
+	if args.Folder != nil {
+		if len(scenes) > 0 {
+			return oerr.CantJoin("you can't give both scenes and --folder")
+		}
+		if scenes, err = folderScenes(*args.Folder); err != nil {
+			return
+		}
+	}
+	if len(scenes) < 2 {
+		return oerr.CantJoin("there must be at least two scenes to join")
+	}
+
+	manuscript, err := ms.LoadContaining(scenes[0])
+	if err != nil {
+		return
+	}
+	for _, warning := range manuscript.Warnings() {
+		cli.Warn("%s: %s", warning.Path, warning.Message)
+	}
+
+	if err = sortScenes(scenes); err != nil {
+		return
+	}
+
+	content, err := joinContent(scenes, !args.NoBreak)
+	if err != nil {
+		return
+	}
+
+	// replace the first scene with the joined text, then remove the rest and close the gaps
+	workList := work.List{}
+	for _, scene := range scenes {
+		var keep string
+		if keep, err = msfs.TrashPath(manuscript.Path(), scene); err != nil {
+			return
+		}
+		workList = work.AppendRemove(workList, scene, keep)
+	}
+	workList = work.AddFileWithContent(workList, scenes[0], content)
+	workList, err = msfs.CloseHoles(workList, filepath.Dir(scenes[0]), scenes[1:], manuscript.NumberWidth())
+	if err != nil {
+		return
+	}
+
+	if args.DryRun || args.PlanJson {
+		return work.Preview(workList, args.PlanJson, os.Stdout)
+	}
+
+	journal, err := msfs.Journal(manuscript.Path())
+	if err != nil {
+		return
+	}
+
+	err = work.Execute(workList, args.Force, journal)
+	if err != nil {
+		return
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package join
+
+import (
+	"gwcoffey/otis/fixture"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestJoinInManuscriptOrder(t *testing.T) {
+	root := fixture.Project(t, "scenes")
+	dir := filepath.Join(root, "manuscript", "00-beginning")
+
+	// the scenes join in manuscript order, whatever order they are given in, and only the first
+	// scene's front matter is kept
+	args := Args{Scenes: []string{filepath.Join(dir, "01-storm.md"), filepath.Join(dir, "00-arrival.md")}, Force: true}
+	if err := Join(&args); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"00-arrival.md", "01-calm.md", "chapter.yml"}
+	if files := fixture.Files(t, dir); !slices.Equal(files, expected) {
+		t.Fatalf("expected %v, got %v", expected, files)
+	}
+	text := "The train came in late.\n\n#\n\nThe storm broke at noon.\n\n#\n\nBy evening the streets were flooded.\n\n#\n\nNobody slept.\n"
+	if content := fixture.Read(t, filepath.Join(dir, "00-arrival.md")); content != text {
+		t.Errorf("expected %q, got %q", text, content)
+	}
+}
+
+func TestJoinFolder(t *testing.T) {
+	root := fixture.Project(t, "scenes")
+	dir := filepath.Join(root, "manuscript", "01-middle")
+
+	if err := Join(&Args{Folder: &dir, NoBreak: true, Force: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	if files := fixture.Files(t, dir); !slices.Equal(files, []string{"00-road.md", "chapter.yml"}) {
+		t.Fatalf("expected the scenes to be joined into the first, got %v", files)
+	}
+	text := "They took the north road.\n\nThe city was empty.\n"
+	if content := fixture.Read(t, filepath.Join(dir, "00-road.md")); content != text {
+		t.Errorf("expected %q, got %q", text, content)
+	}
+}
+
+func TestCantJoin(t *testing.T) {
+	root := fixture.Project(t, "scenes")
+	beginning := filepath.Join(root, "manuscript", "00-beginning")
+	middle := filepath.Join(root, "manuscript", "01-middle")
+	end := filepath.Join(root, "manuscript", "02-end")
+
+	tests := []Args{
+		{Scenes: []string{filepath.Join(beginning, "00-arrival.md")}},
+		{Scenes: []string{filepath.Join(beginning, "00-arrival.md"), filepath.Join(middle, "00-road.md")}},
+		{Scenes: []string{filepath.Join(middle, "00-road.md")}, Folder: &middle},
+		{Folder: &end},
+	}
+	for _, args := range tests {
+		args.Force = true
+		if err := Join(&args); err == nil {
+			t.Errorf("expected %v to fail", args)
+		}
+	}
+
+	original := fixture.Files(t, filepath.Join(fixture.Path("scenes"), "manuscript"))
+	if files := fixture.Files(t, filepath.Join(root, "manuscript")); !slices.Equal(files, original) {
+		t.Errorf("expected nothing to change, got %v", files)
+	}
+}
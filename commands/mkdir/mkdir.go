@@ -1,24 +1,31 @@
 package mkdir
 
 import (
+	"gwcoffey/otis/cli"
 	"gwcoffey/otis/ms"
 	"gwcoffey/otis/msfs"
 	"gwcoffey/otis/work"
+	"os"
 	"path/filepath"
 )
 
 type Args struct {
-	Path  string `arg:"positional,required" help:"where to put the folder"`
-	Name  string `arg:"positional,required" help:"the name of the folder"`
-	At    *int   `arg:"--at,-a" help:"the index at which to insert"`
-	Force bool   `arg:"--force,-f" help:"move other files around without confirmation"`
+	Path     string `arg:"positional,required" help:"where to put the folder"`
+	Name     string `arg:"positional,required" help:"the name of the folder"`
+	At       *int   `arg:"--at,-a" help:"the index at which to insert"`
+	Force    bool   `arg:"--force,-f" help:"move other files around without confirmation"`
+	DryRun   bool   `arg:"--dry-run,-n" help:"show what would change without changing anything"`
+	PlanJson bool   `arg:"--plan-json" help:"print what would change as JSON (for otis apply) without changing anything"`
 }
 
 func MkDir(args *Args) (err error) {
-	_, err = ms.LoadContaining(args.Path)
+	manuscript, err := ms.LoadContaining(args.Path)
 	if err != nil {
 		return
 	}
+	for _, warning := range manuscript.Warnings() {
+		cli.Warn("%s: %s", warning.Path, warning.Message)
+	}
 
 	// if no --at is provided, go to the end of the list
 	var index int
@@ -32,10 +39,19 @@ func MkDir(args *Args) (err error) {
 	}
 
 	// make a work list for this add
-	workList, err := msfs.MakeRoom(args.Path, index)
-	workList = work.AddDir(workList, filepath.Join(args.Path, msfs.MakeDirname(args.Name, index)))
+	workList, err := msfs.MakeRoom(args.Path, index, manuscript.NumberWidth())
+	workList = work.AddDir(workList, filepath.Join(args.Path, msfs.MakeDirname(args.Name, index, manuscript.NumberWidth())))
+
+	if args.DryRun || args.PlanJson {
+		return work.Preview(workList, args.PlanJson, os.Stdout)
+	}
+
+	journal, err := msfs.Journal(manuscript.Path())
+	if err != nil {
+		return
+	}
 
-	err = work.Execute(workList, args.Force)
+	err = work.Execute(workList, args.Force, journal)
 	if err != nil {
 		return
 	}
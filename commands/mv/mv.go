@@ -1,6 +1,7 @@
 package mv
 
 import (
+	"gwcoffey/otis/cli"
 	"gwcoffey/otis/ms"
 	"gwcoffey/otis/msfs"
 	"gwcoffey/otis/oerr"
@@ -8,6 +9,7 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"slices"
 )
 
 type Args struct {
@@ -15,17 +17,19 @@ type Args struct {
 	TargetPath *string `arg:"positional" help:"the target path to move to"`
 	At         *int    `arg:"--at,-a" help:"the scene number at which to insert"`
 	Force      bool    `arg:"--force,-f" help:"move other files around without confirmation"`
+	DryRun     bool    `arg:"--dry-run,-n" help:"show what would change without changing anything"`
+	PlanJson   bool    `arg:"--plan-json" help:"print what would change as JSON (for otis apply) without changing anything"`
 }
 
-func appendMoveToEndOfDir(workList work.List, scene string, dir string) (work.List, error) {
+func appendMoveToEndOfDir(workList work.List, scene string, dir string, width int) (work.List, error) {
 	lastSceneNumber, err := msfs.LastIndex(dir)
 	if err != nil {
 		return nil, err
 	}
 
-	workList = work.AppendMove(workList, scene, filepath.Join(dir, msfs.RenumberFilename(filepath.Base(scene), lastSceneNumber+1)))
+	workList = work.AppendMove(workList, scene, filepath.Join(dir, msfs.RenumberFilename(filepath.Base(scene), lastSceneNumber+1, width)))
 
-	workList, err = closeHole(workList, scene)
+	workList, err = msfs.CloseHole(workList, scene, width)
 	if err != nil {
 		return nil, err
 	}
@@ -33,29 +37,30 @@ func appendMoveToEndOfDir(workList work.List, scene string, dir string) (work.Li
 	return workList, nil
 }
 
-func appendMoveToDirAt(workList work.List, scene string, dir string, sceneNumber int) (work.List, error) {
+func appendMoveToDirAt(workList work.List, scene string, dir string, sceneNumber int, width int) (work.List, error) {
 	entries, err := os.ReadDir(dir)
 	if err != nil {
 		return nil, err
 	}
 
-	// move existing scenes up by one to make a hole
-	for _, entry := range entries {
+	// move existing scenes up by one to make a hole, starting from the last so nothing collides
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
 		var num int
 		num, nerr := msfs.FileNumber(filepath.Join(dir, entry.Name()))
 		if nerr != nil {
 			continue // ignore files with no file number
 		}
-		if num > sceneNumber {
-			workList = work.AppendRename(workList, filepath.Join(dir, entry.Name()), msfs.RenumberFilename(entry.Name(), num+1))
+		if num >= sceneNumber {
+			workList = work.AppendRename(workList, filepath.Join(dir, entry.Name()), msfs.RenumberFilename(entry.Name(), num+1, width))
 		}
 	}
 
 	// move the scene into the hole
-	workList = work.AppendMove(workList, scene, filepath.Join(dir, msfs.RenumberFilename(filepath.Base(scene), sceneNumber)))
+	workList = work.AppendMove(workList, scene, filepath.Join(dir, msfs.RenumberFilename(filepath.Base(scene), sceneNumber, width)))
 
 	// close the hole left behind
-	workList, err = closeHole(workList, scene)
+	workList, err = msfs.CloseHole(workList, scene, width)
 	if err != nil {
 		return nil, err
 	}
@@ -63,33 +68,8 @@ func appendMoveToDirAt(workList work.List, scene string, dir string, sceneNumber
 	return workList, nil
 }
 
-func closeHole(workList work.List, scene string) (work.List, error) {
-	dir := filepath.Dir(scene)
-	sceneNumber, err := msfs.FileNumber(scene)
-	if err != nil {
-		return workList, nil // nothing to do, but not really an error
-	}
-
-	entries, err := os.ReadDir(dir)
-	if err != nil {
-		return workList, err
-	}
-
-	for _, entry := range entries {
-		var num int
-		num, nerr := msfs.FileNumber(entry.Name())
-		if nerr != nil {
-			continue // just ignore files with no number
-		}
-		if num > sceneNumber {
-			workList = work.AppendRename(workList, filepath.Join(dir, entry.Name()), msfs.RenumberFilename(entry.Name(), num-1))
-		}
-	}
-
-	return workList, nil
-}
-
 func appendMoveInSameDir(workList work.List, manuscript ms.Manuscript, scene string, sceneNumber int) (work.List, error) {
+	width := manuscript.NumberWidth()
 	tmp, err := msfs.TmpDir(manuscript.Path())
 	if err != nil {
 		return nil, err
@@ -108,7 +88,8 @@ func appendMoveInSameDir(workList work.List, manuscript ms.Manuscript, scene str
 		return nil, err
 	}
 
-	if lastScene == originalSceneNumber {
+	// a numbered scene leaves its own number behind, so it can go no further than the last number
+	if nerr == nil {
 		lastScene = lastScene - 1
 	}
 
@@ -129,6 +110,12 @@ func appendMoveInSameDir(workList work.List, manuscript ms.Manuscript, scene str
 		return nil, err
 	}
 
+	// scenes shift down when the scene moves later and up when it moves earlier; either way, the
+	// scene nearest its original position must move first so nothing collides
+	if sceneNumber < originalSceneNumber {
+		slices.Reverse(entries)
+	}
+
 	for _, entry := range entries {
 		num, nerr := msfs.FileNumber(entry.Name())
 		if num == originalSceneNumber || nerr != nil {
@@ -136,15 +123,15 @@ func appendMoveInSameDir(workList work.List, manuscript ms.Manuscript, scene str
 			continue
 		} else if num >= originalSceneNumber && num <= sceneNumber {
 			// scenes between the scene and its new position need to move down to fill the space
-			workList = work.AppendRename(workList, filepath.Join(filepath.Dir(scene), entry.Name()), msfs.RenumberFilename(entry.Name(), num-1))
+			workList = work.AppendRename(workList, filepath.Join(filepath.Dir(scene), entry.Name()), msfs.RenumberFilename(entry.Name(), num-1, width))
 		} else if num >= sceneNumber && num <= originalSceneNumber {
 			// scenes after the target and before the scene need to move up to make space
-			workList = work.AppendRename(workList, filepath.Join(filepath.Dir(scene), entry.Name()), msfs.RenumberFilename(entry.Name(), num+1))
+			workList = work.AppendRename(workList, filepath.Join(filepath.Dir(scene), entry.Name()), msfs.RenumberFilename(entry.Name(), num+1, width))
 		}
 	}
 
 	// move the scene to from tmp to the target
-	workList = work.AppendMove(workList, tmpFile, filepath.Join(filepath.Dir(scene), msfs.RenumberFilename(filepath.Base(scene), sceneNumber)))
+	workList = work.AppendMove(workList, tmpFile, filepath.Join(filepath.Dir(scene), msfs.RenumberFilename(filepath.Base(scene), sceneNumber, width)))
 
 	return workList, nil
 }
@@ -154,13 +141,16 @@ func Mv(args *Args) (err error) {
 	if err != nil {
 		return
 	}
+	for _, warning := range manuscript.Warnings() {
+		cli.Warn("%s: %s", warning.Path, warning.Message)
+	}
 
 	workList := work.List{}
 	if args.TargetPath != nil {
 		if args.At == nil {
-			workList, err = appendMoveToEndOfDir(workList, args.Path, *args.TargetPath)
+			workList, err = appendMoveToEndOfDir(workList, args.Path, *args.TargetPath, manuscript.NumberWidth())
 		} else {
-			workList, err = appendMoveToDirAt(workList, args.Path, *args.TargetPath, *args.At)
+			workList, err = appendMoveToDirAt(workList, args.Path, *args.TargetPath, *args.At, manuscript.NumberWidth())
 		}
 	} else if args.At != nil {
 		workList, err = appendMoveInSameDir(workList, manuscript, args.Path, *args.At)
@@ -172,7 +162,16 @@ func Mv(args *Args) (err error) {
 		return
 	}
 
-	err = work.Execute(workList, args.Force)
+	if args.DryRun || args.PlanJson {
+		return work.Preview(workList, args.PlanJson, os.Stdout)
+	}
+
+	journal, err := msfs.Journal(manuscript.Path())
+	if err != nil {
+		return
+	}
+
+	err = work.Execute(workList, args.Force, journal)
 	if err != nil {
 		return
 	}
@@ -0,0 +1,55 @@
+package mv
+
+import (
+	"gwcoffey/otis/fixture"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestMvAtOccupiedSlot(t *testing.T) {
+	root := fixture.Project(t, "scenes")
+	beginning := filepath.Join(root, "manuscript", "00-beginning")
+	middle := filepath.Join(root, "manuscript", "01-middle")
+
+	at := 1
+	err := Mv(&Args{Path: filepath.Join(middle, "01-city.md"), TargetPath: &beginning, At: &at, Force: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the scenes from 1 on move up to make room
+	expected := []string{"00-arrival.md", "01-city.md", "02-storm.md", "03-calm.md", "chapter.yml"}
+	if files := fixture.Files(t, beginning); !slices.Equal(files, expected) {
+		t.Errorf("expected %v, got %v", expected, files)
+	}
+	if text := fixture.Read(t, filepath.Join(beginning, "01-city.md")); text != "The city was empty.\n" {
+		t.Errorf("expected the moved scene at 1, got %q", text)
+	}
+	if files := fixture.Files(t, middle); !slices.Equal(files, []string{"00-road.md", "chapter.yml"}) {
+		t.Errorf("expected the moved scene to be gone, got %v", files)
+	}
+}
+
+func TestMvInSameDir(t *testing.T) {
+	tests := []struct {
+		scene    string
+		at       int
+		expected []string
+	}{
+		{"02-calm.md", 0, []string{"00-calm.md", "01-arrival.md", "02-storm.md", "chapter.yml"}},
+		{"00-arrival.md", 2, []string{"00-storm.md", "01-calm.md", "02-arrival.md", "chapter.yml"}},
+		{"01-storm.md", 9, []string{"00-arrival.md", "01-calm.md", "02-storm.md", "chapter.yml"}},
+	}
+	for _, test := range tests {
+		root := fixture.Project(t, "scenes")
+		dir := filepath.Join(root, "manuscript", "00-beginning")
+
+		if err := Mv(&Args{Path: filepath.Join(dir, test.scene), At: &test.at, Force: true}); err != nil {
+			t.Fatal(err)
+		}
+		if files := fixture.Files(t, dir); !slices.Equal(files, test.expected) {
+			t.Errorf("moving %s to %d: expected %v, got %v", test.scene, test.at, test.expected, files)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package progress
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"gwcoffey/otis/git"
+	ms2 "gwcoffey/otis/ms"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// historyFilename is the progress log, kept at the project root so it can be committed along
+// with the manuscript
+const historyFilename = "progress.csv"
+
+const dateFormat = "2006-01-02"
+
+// history maps a day (in dateFormat) to the manuscript's word count at the end of that day
+type history map[string]int
+
+func historyPath(m ms2.Manuscript) string {
+	return filepath.Join(m.Path(), historyFilename)
+}
+
+func readHistory(m ms2.Manuscript) (h history, err error) {
+	h = history{}
+
+	file, err := os.Open(historyPath(m))
+	if errors.Is(err, fs.ErrNotExist) {
+		return h, nil
+	} else if err != nil {
+		return
+	}
+	defer func() { _ = file.Close() }()
+
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		return
+	}
+	for i, record := range records {
+		if i == 0 && record[0] == "date" {
+			continue // header
+		}
+		if len(record) != 2 {
+			return nil, fmt.Errorf("%s line %d: expected date,words", historyFilename, i+1)
+		}
+		if _, err = time.Parse(dateFormat, record[0]); err != nil {
+			return nil, fmt.Errorf("%s line %d: %w", historyFilename, i+1, err)
+		}
+		var count int
+		if count, err = strconv.Atoi(record[1]); err != nil {
+			return nil, fmt.Errorf("%s line %d: %w", historyFilename, i+1, err)
+		}
+		h[record[0]] = count
+	}
+	return
+}
+
+// days returns the recorded days in order
+func (h history) days() []string {
+	var days []string
+	for day := range h {
+		days = append(days, day)
+	}
+	sort.Strings(days)
+	return days
+}
+
+func writeHistory(m ms2.Manuscript, h history) (err error) {
+	file, err := os.Create(historyPath(m))
+	if err != nil {
+		return
+	}
+	defer func() {
+		if cerr := file.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	w := csv.NewWriter(file)
+	_ = w.Write([]string{"date", "words"})
+	for _, day := range h.days() {
+		_ = w.Write([]string{day, strconv.Itoa(h[day])})
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// wordCountAt counts the words in the manuscript as of a git commit
+func wordCountAt(m ms2.Manuscript, hash string) (count int, err error) {
+	counts, err := ms2.SceneWordCountsAt(m, hash)
+	for _, c := range counts {
+		count += c
+	}
+	return
+}
+
+// backfill adds the word count at the last commit of each day that isn't already recorded,
+// returning the number of days added
+func backfill(m ms2.Manuscript, h history) (added int, err error) {
+	commits, err := git.Log(m.Path(), "manuscript")
+	if err != nil {
+		return
+	}
+
+	// commits are oldest first, so the last one seen for a day is the end of that day
+	last := map[string]string{}
+	for _, commit := range commits {
+		last[commit.Time.Format(dateFormat)] = commit.Hash
+	}
+
+	for day, hash := range last {
+		if _, recorded := h[day]; recorded {
+			continue
+		}
+		if h[day], err = wordCountAt(m, hash); err != nil {
+			return
+		}
+		added++
+	}
+	return
+}
@@ -0,0 +1,104 @@
+package progress
+
+import (
+	"gwcoffey/otis/fixture"
+	"gwcoffey/otis/ms"
+	"maps"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// datedRepo makes a git repository of the scenes project written over three days: 36 words on
+// January 5, 40 by the end of January 6 (over two commits), and a change to otis.yml alone on
+// January 7
+func datedRepo(t *testing.T) string {
+	t.Setenv("GIT_COMMITTER_DATE", "2026-01-05T12:00:00+0000")
+	root := fixture.Repo(t, "scenes")
+	manuscript := filepath.Join(root, "manuscript")
+
+	t.Setenv("GIT_COMMITTER_DATE", "2026-01-06T09:00:00+0000")
+	appendTo(t, filepath.Join(manuscript, "00-beginning", "02-calm.md"), "More rain.\n")
+	fixture.Git(t, root, "commit", "--quiet", "--all", "--message", "rain")
+
+	t.Setenv("GIT_COMMITTER_DATE", "2026-01-06T18:00:00+0000")
+	appendTo(t, filepath.Join(manuscript, "01-middle", "02-night.md"), "Night fell.\n")
+	fixture.Git(t, root, "add", ".")
+	fixture.Git(t, root, "commit", "--quiet", "--message", "night")
+
+	t.Setenv("GIT_COMMITTER_DATE", "2026-01-07T12:00:00+0000")
+	appendTo(t, filepath.Join(root, "otis.yml"), "targetWords: 1000\n")
+	fixture.Git(t, root, "commit", "--quiet", "--all", "--message", "target")
+	return root
+}
+
+func appendTo(t *testing.T, path string, text string) {
+	t.Helper()
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = file.Close() }()
+	if _, err = file.WriteString(text); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBackfill(t *testing.T) {
+	m, err := ms.Load(datedRepo(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// each day gets the count at its last commit, and only days that changed the manuscript count
+	h := history{}
+	added, err := backfill(m, h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := history{"2026-01-05": 36, "2026-01-06": 40}
+	if added != 2 || !maps.Equal(h, expected) {
+		t.Errorf("expected %v, got %d days: %v", expected, added, h)
+	}
+
+	// days already recorded are left alone
+	h = history{"2026-01-05": 30}
+	if added, err = backfill(m, h); err != nil {
+		t.Fatal(err)
+	}
+	expected = history{"2026-01-05": 30, "2026-01-06": 40}
+	if added != 1 || !maps.Equal(h, expected) {
+		t.Errorf("expected %v, got %d days: %v", expected, added, h)
+	}
+}
+
+func TestHistoryRoundTrip(t *testing.T) {
+	m, err := ms.Load(fixture.Project(t, "scenes"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a project starts with no history
+	h, err := readHistory(m)
+	if err != nil || len(h) != 0 {
+		t.Fatalf("expected no history, got %v %v", h, err)
+	}
+
+	h = history{"2026-01-06": 40, "2026-01-05": 36}
+	if err = writeHistory(m, h); err != nil {
+		t.Fatal(err)
+	}
+	if text := fixture.Read(t, historyPath(m)); text != "date,words\n2026-01-05,36\n2026-01-06,40\n" {
+		t.Errorf("expected the days in order, got %q", text)
+	}
+	if read, err := readHistory(m); err != nil || !maps.Equal(read, h) {
+		t.Errorf("expected %v, got %v %v", h, read, err)
+	}
+
+	if err = os.WriteFile(historyPath(m), []byte("date,words\n2026-01-05,many\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = readHistory(m); err == nil {
+		t.Error("expected a bad count to fail")
+	}
+}
@@ -0,0 +1,160 @@
+package progress
+
+import (
+	"fmt"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"gwcoffey/otis/git"
+	ms2 "gwcoffey/otis/ms"
+	"gwcoffey/otis/oerr"
+	"math"
+	"time"
+)
+
+type Args struct {
+	ProjectPath *string `arg:"positional" help:"path to the otis project"`
+	Backfill    bool    `arg:"--backfill,-b" help:"fill in past days from the git history"`
+	Days        int     `arg:"--days,-d" help:"how many days to show" default:"7"`
+	Weeks       int     `arg:"--weeks,-w" help:"how many weeks to show" default:"4"`
+}
+
+// written returns the words written on a day: the change since the previous recorded day (the
+// first recorded day is just a starting point, so nothing was written on it)
+func (h history) written(day string) int {
+	count, ok := h[day]
+	if !ok {
+		return 0
+	}
+	previous := -1
+	for _, d := range h.days() {
+		if d >= day {
+			break
+		}
+		previous = h[d]
+	}
+	if previous < 0 {
+		return 0
+	}
+	return count - previous
+}
+
+// streak returns the number of consecutive days, ending today (or yesterday, if nothing has been
+// written yet today), on which words were written
+func (h history) streak(today time.Time) (streak int) {
+	day := today
+	if h.written(day.Format(dateFormat)) <= 0 {
+		day = day.AddDate(0, 0, -1)
+	}
+	for h.written(day.Format(dateFormat)) > 0 {
+		streak++
+		day = day.AddDate(0, 0, -1)
+	}
+	return
+}
+
+// startOfWeek returns the Monday of the week containing t
+func startOfWeek(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	return t.AddDate(0, 0, -offset)
+}
+
+func printReport(m ms2.Manuscript, h history, today time.Time, args *Args) {
+	out := message.NewPrinter(language.English)
+	heading := func(s string) {
+		out.Printf("\033[94m%s\033[0m\n", s)
+	}
+
+	heading("Daily")
+	for i := args.Days - 1; i >= 0; i-- {
+		day := today.AddDate(0, 0, -i)
+		key := day.Format(dateFormat)
+		if count, ok := h[key]; ok {
+			out.Printf("  %s %s : %+7d  (%d total)\n", key, day.Format("Mon"), h.written(key), count)
+		} else {
+			out.Printf("  %s %s : %7s\n", key, day.Format("Mon"), "-")
+		}
+	}
+
+	heading("Weekly")
+	for i := args.Weeks - 1; i >= 0; i-- {
+		start := startOfWeek(today).AddDate(0, 0, -7*i)
+		written := 0
+		for d := 0; d < 7; d++ {
+			written += h.written(start.AddDate(0, 0, d).Format(dateFormat))
+		}
+		out.Printf("  week of %s : %+7d\n", start.Format(dateFormat), written)
+	}
+
+	heading("Progress")
+	streak := h.streak(today)
+	if streak == 1 {
+		out.Printf("  streak: 1 day\n")
+	} else {
+		out.Printf("  streak: %d days\n", streak)
+	}
+
+	total := h[today.Format(dateFormat)]
+	if m.TargetWords() != nil && *m.TargetWords() > 0 {
+		target := *m.TargetWords()
+		out.Printf("  %d of %d words (%d%%)\n", total, target, total*100/target)
+
+		if m.Deadline() != nil {
+			daysLeft := int(math.Ceil(m.Deadline().Sub(today).Hours() / 24))
+			switch {
+			case total >= target:
+				out.Printf("  target reached\n")
+			case daysLeft <= 0:
+				out.Printf("  deadline %s has passed\n", m.Deadline().Format(dateFormat))
+			default:
+				out.Printf("  %d days until %s: %d words per day needed\n", daysLeft,
+					m.Deadline().Format(dateFormat), int(math.Ceil(float64(target-total)/float64(daysLeft))))
+			}
+		}
+	} else {
+		out.Printf("  %d words (set targetWords in otis.yml to track progress toward a goal)\n", total)
+	}
+}
+
+func Progress(args *Args) (err error) {
+	var manuscript ms2.Manuscript
+
+	if args.ProjectPath == nil {
+		manuscript, err = ms2.LoadHere()
+	} else {
+		manuscript, err = ms2.Load(*args.ProjectPath)
+	}
+	if err != nil {
+		return
+	}
+
+	h, err := readHistory(manuscript)
+	if err != nil {
+		return
+	}
+
+	if args.Backfill {
+		if !git.IsRepo(manuscript.Path()) {
+			return oerr.NotAGitRepo(manuscript.Path())
+		}
+		var added int
+		added, err = backfill(manuscript, h)
+		if err != nil {
+			return
+		}
+		fmt.Printf("backfilled %d days from git history\n", added)
+	}
+
+	// record today's total, replacing any earlier count from today
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	h[today.Format(dateFormat)], err = ms2.WordCount(manuscript)
+	if err != nil {
+		return
+	}
+	if err = writeHistory(manuscript, h); err != nil {
+		return
+	}
+
+	printReport(manuscript, h, today, args)
+	return nil
+}
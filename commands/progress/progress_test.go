@@ -0,0 +1,62 @@
+package progress
+
+import (
+	"gwcoffey/otis/fixture"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWritten(t *testing.T) {
+	h := history{"2026-01-05": 100, "2026-01-06": 150, "2026-01-07": 150, "2026-01-09": 120}
+
+	// the first day is a starting point, and a day's words are counted from the last recorded day
+	// before it
+	expected := map[string]int{"2026-01-04": 0, "2026-01-05": 0, "2026-01-06": 50, "2026-01-07": 0, "2026-01-08": 0, "2026-01-09": -30}
+	for day, words := range expected {
+		if written := h.written(day); written != words {
+			t.Errorf("expected %d words on %s, got %d", words, day, written)
+		}
+	}
+}
+
+func TestStreak(t *testing.T) {
+	h := history{"2026-01-05": 100, "2026-01-06": 150, "2026-01-07": 200, "2026-01-08": 260}
+	day := func(d int) time.Time { return time.Date(2026, 1, d, 0, 0, 0, 0, time.Local) }
+
+	// the streak still counts if nothing has been written yet today, but not after a day off
+	expected := map[int]int{8: 3, 9: 3, 10: 0}
+	for d, streak := range expected {
+		if actual := h.streak(day(d)); actual != streak {
+			t.Errorf("expected a streak of %d on January %d, got %d", streak, d, actual)
+		}
+	}
+}
+
+func TestProgress(t *testing.T) {
+	root := datedRepo(t)
+
+	out := fixture.Stdout(t, func() {
+		if err := Progress(&Args{ProjectPath: &root, Backfill: true, Days: 7, Weeks: 4}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if !strings.Contains(out, "backfilled 2 days from git history") || !strings.Contains(out, "40 of 1,000 words (4%)") {
+		t.Errorf("expected a backfilled report, got\n%s", out)
+	}
+
+	// today is recorded along with the backfilled days
+	today := time.Now().Format(dateFormat)
+	expected := "date,words\n2026-01-05,36\n2026-01-06,40\n" + today + ",40\n"
+	if text := fixture.Read(t, filepath.Join(root, historyFilename)); text != expected {
+		t.Errorf("expected %q, got %q", expected, text)
+	}
+}
+
+func TestBackfillNeedsGit(t *testing.T) {
+	root := fixture.Project(t, "scenes")
+	if err := Progress(&Args{ProjectPath: &root, Backfill: true}); err == nil {
+		t.Error("expected backfilling outside a git repository to fail")
+	}
+}
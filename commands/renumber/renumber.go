@@ -0,0 +1,158 @@
+package renumber
+
+import (
+	"fmt"
+	"gwcoffey/otis/ms"
+	"gwcoffey/otis/msfs"
+	"gwcoffey/otis/work"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type Args struct {
+	Path     *string `arg:"positional" help:"the folder to renumber (default: the whole manuscript)"`
+	Width    *int    `arg:"--width,-w" help:"how many digits to use in each number (default: numberWidth from otis.yml)"`
+	Force    bool    `arg:"--force,-f" help:"rename files without confirmation"`
+	DryRun   bool    `arg:"--dry-run,-n" help:"show what would change without changing anything"`
+	PlanJson bool    `arg:"--plan-json" help:"print what would change as JSON (for otis apply) without changing anything"`
+}
+
+// item is a scene or folder in a directory, in the order it should be numbered
+type item struct {
+	name   string
+	isDir  bool
+	number int
+}
+
+// items returns the scenes and folders in a directory, in number order (ties are broken by name,
+// and items with no number come last)
+func items(dir string) (result []item, err error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") || (!entry.IsDir() && filepath.Ext(entry.Name()) != ".md") {
+			continue
+		}
+		number, nerr := msfs.FileNumber(entry.Name())
+		if nerr != nil {
+			number = -1
+		}
+		result = append(result, item{name: entry.Name(), isDir: entry.IsDir(), number: number})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if (a.number < 0) != (b.number < 0) {
+			return b.number < 0
+		}
+		return a.number < b.number
+	})
+	return
+}
+
+// renumberDir adds the renames that number the directory's items 0..N to the work list; the
+// contents of each folder are renumbered before the folder itself, so every path is still valid
+// when its rename happens
+func renumberDir(workList work.List, dir string, width int) (work.List, error) {
+	children, err := items(dir)
+	if err != nil {
+		return workList, err
+	}
+
+	type rename struct {
+		from string
+		to   string
+	}
+	var pending []rename
+	for i, child := range children {
+		if child.isDir {
+			if workList, err = renumberDir(workList, filepath.Join(dir, child.name), width); err != nil {
+				return workList, err
+			}
+		}
+		if newName := msfs.RenumberFilename(child.name, i, width); newName != child.name {
+			pending = append(pending, rename{from: child.name, to: newName})
+		}
+	}
+
+	// a rename can't happen until the item already using its new name has been renamed
+	for len(pending) > 0 {
+		var waiting []rename
+		for _, r := range pending {
+			blocked := false
+			for _, other := range pending {
+				if other.from == r.to {
+					blocked = true
+				}
+			}
+			if blocked {
+				waiting = append(waiting, r)
+			} else {
+				workList = work.AppendRename(workList, filepath.Join(dir, r.from), r.to)
+			}
+		}
+		if len(waiting) == len(pending) {
+			return workList, fmt.Errorf("can't find an order to renumber the items in %s", dir)
+		}
+		pending = waiting
+	}
+
+	return workList, nil
+}
+
+func Renumber(args *Args) (err error) {
+	var manuscript ms.Manuscript
+	if args.Path == nil {
+		manuscript, err = ms.LoadHere()
+	} else {
+		manuscript, err = ms.LoadContaining(*args.Path)
+	}
+	if err != nil {
+		return
+	}
+
+	dir := filepath.Join(manuscript.Path(), "manuscript")
+	if args.Path != nil {
+		dir = *args.Path
+	} else if cwd, werr := os.Getwd(); werr == nil {
+		// keep the paths short when listing the changes
+		if rel, rerr := filepath.Rel(cwd, dir); rerr == nil && !strings.HasPrefix(rel, "..") {
+			dir = rel
+		}
+	}
+
+	width := manuscript.NumberWidth()
+	if args.Width != nil {
+		width = *args.Width
+	}
+
+	workList, err := renumberDir(work.List{}, dir, width)
+	if err != nil {
+		return
+	}
+	if len(workList) == 0 {
+		fmt.Println("everything is already numbered")
+		return nil
+	}
+
+	if args.DryRun || args.PlanJson {
+		return work.Preview(workList, args.PlanJson, os.Stdout)
+	}
+
+	journal, err := msfs.Journal(manuscript.Path())
+	if err != nil {
+		return
+	}
+
+	err = work.Execute(workList, args.Force, journal)
+	if err != nil {
+		return
+	}
+
+	return nil
+}
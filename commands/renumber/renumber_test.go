@@ -0,0 +1,97 @@
+package renumber
+
+import (
+	"gwcoffey/otis/fixture"
+	"gwcoffey/otis/work"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRenumber(t *testing.T) {
+	root := fixture.Project(t, "messy")
+	fixture.Chdir(t, root)
+
+	if err := Renumber(&Args{Force: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	// duplicates keep their order by name, and unnumbered scenes go last
+	manuscript := filepath.Join(root, "manuscript")
+	expected := map[string]string{
+		"00-first/00-a.md":     "A.\n",
+		"00-first/01-b.md":     "B.\n",
+		"00-first/02-c.md":     "C.\n",
+		"00-first/chapter.yml": "title: First\n",
+		// 01-scene.md has to become 00-scene.md before 1-scene.md can take its name
+		"01-second/00-scene.md": "One.\n",
+		"01-second/01-scene.md": "One again.\n",
+		"01-second/02-scene.md": "Three.\n",
+		"01-second/03-coda.md":  "Coda.\n",
+		"01-second/chapter.yml": "title: Second\n",
+	}
+	files := fixture.Files(t, manuscript)
+	if len(files) != len(expected) {
+		t.Fatalf("expected %d files, got %v", len(expected), files)
+	}
+	for _, name := range files {
+		if text, ok := expected[name]; !ok {
+			t.Errorf("unexpected file %s", name)
+		} else if content := fixture.Read(t, filepath.Join(manuscript, name)); content != text {
+			t.Errorf("expected %s to be %q, got %q", name, text, content)
+		}
+	}
+
+	out := fixture.Stdout(t, func() {
+		if err := Renumber(&Args{Force: true}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if !strings.Contains(out, "already numbered") {
+		t.Errorf("expected nothing left to renumber, got %q", out)
+	}
+}
+
+func TestRenumberFolderWithWidth(t *testing.T) {
+	root := fixture.Project(t, "messy")
+	dir := filepath.Join(root, "manuscript", "01-first")
+
+	width := 3
+	if err := Renumber(&Args{Path: &dir, Width: &width, Force: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	// only the folder's contents are renumbered
+	expected := []string{"000-a.md", "001-b.md", "002-c.md", "chapter.yml"}
+	if files := fixture.Files(t, dir); !slices.Equal(files, expected) {
+		t.Errorf("expected %v, got %v", expected, files)
+	}
+	expected = []string{"01-scene.md", "03-scene.md", "1-scene.md", "chapter.yml", "coda.md"}
+	if files := fixture.Files(t, filepath.Join(root, "manuscript", "03-second")); !slices.Equal(files, expected) {
+		t.Errorf("expected %v, got %v", expected, files)
+	}
+}
+
+func TestRenumberDirOrder(t *testing.T) {
+	dir := filepath.Join(fixture.Path("messy"), "manuscript", "03-second")
+	list, err := renumberDir(nil, dir, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the rename that frees a name always comes before the rename that takes it
+	var order []string
+	for _, line := range strings.Split(strings.TrimSpace(work.PrintableString(list)), "\n") {
+		order = append(order, strings.TrimSpace(line))
+	}
+	expected := []string{
+		"RENAME " + filepath.Join(dir, "01-scene.md") + " → 00-scene.md",
+		"RENAME " + filepath.Join(dir, "03-scene.md") + " → 02-scene.md",
+		"RENAME " + filepath.Join(dir, "coda.md") + " → 03-coda.md",
+		"RENAME " + filepath.Join(dir, "1-scene.md") + " → 01-scene.md",
+	}
+	if !slices.Equal(order, expected) {
+		t.Errorf("expected\n%s\ngot\n%s", strings.Join(expected, "\n"), strings.Join(order, "\n"))
+	}
+}
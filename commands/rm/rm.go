@@ -0,0 +1,90 @@
+package rm
+
+import (
+	"errors"
+	"fmt"
+	"gwcoffey/otis/cli"
+	"gwcoffey/otis/ms"
+	"gwcoffey/otis/msfs"
+	"gwcoffey/otis/work"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+type Args struct {
+	Path     string `arg:"positional,required" help:"the scene or folder to remove"`
+	Trash    bool   `arg:"--trash,-t" help:"move it to the project's archive folder instead of deleting it"`
+	Force    bool   `arg:"--force,-f" help:"move other files around without confirmation"`
+	DryRun   bool   `arg:"--dry-run,-n" help:"show what would change without changing anything"`
+	PlanJson bool   `arg:"--plan-json" help:"print what would change as JSON (for otis apply) without changing anything"`
+}
+
+// archivePath returns where to keep the removed item in the archive folder: the same place it had
+// in the manuscript, with a suffix if something is already archived there
+func archivePath(manuscript ms.Manuscript, path string) (string, error) {
+	msDir, err := filepath.Abs(filepath.Join(manuscript.Path(), "manuscript"))
+	if err != nil {
+		return "", err
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(msDir, abs)
+	if err != nil {
+		return "", err
+	}
+
+	keep := filepath.Join(msfs.ArchiveDir(manuscript.Path()), rel)
+	ext := filepath.Ext(keep)
+	for i := 2; ; i++ {
+		if _, err = os.Stat(keep); errors.Is(err, fs.ErrNotExist) {
+			return keep, nil
+		}
+		keep = fmt.Sprintf("%s-%d%s", filepath.Join(msfs.ArchiveDir(manuscript.Path()), rel[:len(rel)-len(ext)]), i, ext)
+	}
+}
+
+func Rm(args *Args) (err error) {
+	manuscript, err := ms.LoadContaining(args.Path)
+	if err != nil {
+		return
+	}
+	for _, warning := range manuscript.Warnings() {
+		cli.Warn("%s: %s", warning.Path, warning.Message)
+	}
+
+	var keep string
+	if args.Trash {
+		keep, err = archivePath(manuscript, args.Path)
+	} else {
+		keep, err = msfs.TrashPath(manuscript.Path(), args.Path)
+	}
+	if err != nil {
+		return
+	}
+
+	// remove the item and then close the hole it leaves behind
+	workList := work.AppendRemove(work.List{}, args.Path, keep)
+	workList, err = msfs.CloseHole(workList, args.Path, manuscript.NumberWidth())
+	if err != nil {
+		return
+	}
+
+	if args.DryRun || args.PlanJson {
+		return work.Preview(workList, args.PlanJson, os.Stdout)
+	}
+
+	journal, err := msfs.Journal(manuscript.Path())
+	if err != nil {
+		return
+	}
+
+	err = work.Execute(workList, args.Force, journal)
+	if err != nil {
+		return
+	}
+
+	return nil
+}
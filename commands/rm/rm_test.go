@@ -0,0 +1,87 @@
+package rm
+
+import (
+	"gwcoffey/otis/fixture"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestRmScene(t *testing.T) {
+	root := fixture.Project(t, "scenes")
+	dir := filepath.Join(root, "manuscript", "00-beginning")
+
+	if err := Rm(&Args{Path: filepath.Join(dir, "00-arrival.md"), Force: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the later scenes close the gap, and the scene is kept in the trash for undo
+	expected := []string{"00-storm.md", "01-calm.md", "chapter.yml"}
+	if files := fixture.Files(t, dir); !slices.Equal(files, expected) {
+		t.Errorf("expected %v, got %v", expected, files)
+	}
+	trashed, err := filepath.Glob(filepath.Join(root, ".build", "trash", "*", "00-arrival.md"))
+	if err != nil || len(trashed) != 1 {
+		t.Fatal("expected the scene in the trash, got", trashed, err)
+	}
+	if text := fixture.Read(t, trashed[0]); text != "The train came in late.\n" {
+		t.Errorf("expected the removed scene, got %q", text)
+	}
+}
+
+func TestRmFolderToArchive(t *testing.T) {
+	root := fixture.Project(t, "scenes")
+	manuscript := filepath.Join(root, "manuscript")
+
+	if err := Rm(&Args{Path: filepath.Join(manuscript, "01-middle"), Trash: true, Force: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Rm(&Args{Path: filepath.Join(manuscript, "00-beginning", "02-calm.md"), Trash: true, Force: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"00-beginning/00-arrival.md",
+		"00-beginning/01-storm.md",
+		"00-beginning/chapter.yml",
+		"01-end/00-home.md",
+	}
+	if files := fixture.Files(t, manuscript); !slices.Equal(files, expected) {
+		t.Errorf("expected %v, got %v", expected, files)
+	}
+
+	// removed items keep their place in the archive
+	expected = []string{
+		"00-beginning/02-calm.md",
+		"01-middle/00-road.md",
+		"01-middle/01-city.md",
+		"01-middle/chapter.yml",
+	}
+	if files := fixture.Files(t, filepath.Join(root, "archive")); !slices.Equal(files, expected) {
+		t.Errorf("expected %v in the archive, got %v", expected, files)
+	}
+}
+
+func TestArchivePathAvoidsCollisions(t *testing.T) {
+	root := fixture.Project(t, "scenes")
+	archived := filepath.Join(root, "archive", "00-beginning")
+	if err := os.MkdirAll(archived, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(archived, "00-arrival.md"), []byte("An older draft.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Rm(&Args{Path: filepath.Join(root, "manuscript", "00-beginning", "00-arrival.md"), Trash: true, Force: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the scene archived before is left alone
+	if files := fixture.Files(t, archived); !slices.Equal(files, []string{"00-arrival-2.md", "00-arrival.md"}) {
+		t.Errorf("expected both scenes in the archive, got %v", files)
+	}
+	if text := fixture.Read(t, filepath.Join(archived, "00-arrival.md")); text != "An older draft.\n" {
+		t.Errorf("expected the older scene to be kept, got %q", text)
+	}
+}
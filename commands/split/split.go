@@ -0,0 +1,162 @@
+package split
+
+import (
+	"fmt"
+	"gwcoffey/otis/cli"
+	"gwcoffey/otis/ms"
+	"gwcoffey/otis/msfs"
+	"gwcoffey/otis/oerr"
+	"gwcoffey/otis/work"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type Args struct {
+	Path       string `arg:"positional,required" help:"the scene to split"`
+	AtLine     *int   `arg:"--at-line,-l" help:"the line (of the file) that starts the second scene"`
+	AtMarker   bool   `arg:"--at-marker,-m" help:"split at the first scene break (a line with just #)"`
+	AllMarkers bool   `arg:"--all-markers,-a" help:"split at every scene break"`
+	Force      bool   `arg:"--force,-f" help:"move other files around without confirmation"`
+	DryRun     bool   `arg:"--dry-run,-n" help:"show what would change without changing anything"`
+	PlanJson   bool   `arg:"--plan-json" help:"print what would change as JSON (for otis apply) without changing anything"`
+}
+
+// isMarker reports whether a line is a scene break
+func isMarker(line string) bool {
+	return strings.TrimSpace(line) == "#"
+}
+
+// trimBlankLines removes blank lines from the start and end of a part, and makes it end with a
+// newline
+func trimBlankLines(lines []string) string {
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// splitBody breaks the body of a scene into parts; skip is the number of front matter lines
+// before the body, so args.AtLine can count lines of the whole file
+func splitBody(body string, skip int, args *Args) (parts []string, err error) {
+	lines := strings.Split(strings.TrimSuffix(body, "\n"), "\n")
+
+	var start int
+	addPart := func(end int) {
+		if part := trimBlankLines(lines[start:end]); part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	if args.AtLine != nil {
+		at := *args.AtLine - skip - 1
+		if at <= 0 || at >= len(lines) {
+			return nil, fmt.Errorf("line %d is not inside the scene", *args.AtLine)
+		}
+		addPart(at)
+		start = at
+	} else {
+		for i, line := range lines {
+			if isMarker(line) {
+				addPart(i)
+				start = i + 1
+				if args.AtMarker {
+					break
+				}
+			}
+		}
+	}
+	addPart(len(lines))
+
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("it has no text on one side of the split")
+	}
+	return
+}
+
+// partFilename returns the name of the scene file for the given part: the first keeps the
+// original name, and the rest get a numbered suffix
+func partFilename(name string, num int, part int, width int) string {
+	if part > 0 {
+		ext := filepath.Ext(name)
+		name = fmt.Sprintf("%s-%d%s", strings.TrimSuffix(name, ext), part+1, ext)
+	}
+	return msfs.RenumberFilename(name, num+part, width)
+}
+
+func Split(args *Args) (err error) {
+	flags := 0
+	for _, set := range []bool{args.AtLine != nil, args.AtMarker, args.AllMarkers} {
+		if set {
+			flags++
+		}
+	}
+	if flags != 1 {
+		return oerr.SplitPointRequired()
+	}
+
+	manuscript, err := ms.LoadContaining(args.Path)
+	if err != nil {
+		return
+	}
+	for _, warning := range manuscript.Warnings() {
+		cli.Warn("%s: %s", warning.Path, warning.Message)
+	}
+
+	num, err := msfs.FileNumber(args.Path)
+	if err != nil {
+		return
+	}
+
+	content, err := os.ReadFile(args.Path)
+	if err != nil {
+		return
+	}
+
+	// every part keeps the scene's front matter
+	frontMatter, body := ms.SplitSceneFile(content)
+	parts, err := splitBody(string(body), strings.Count(string(frontMatter), "\n"), args)
+	if err != nil {
+		return oerr.NoSplitPoint(args.Path, err.Error())
+	}
+
+	keep, err := msfs.TrashPath(manuscript.Path(), args.Path)
+	if err != nil {
+		return
+	}
+
+	// replace the scene with its parts, moving the later scenes up to make room
+	dir := filepath.Dir(args.Path)
+	workList := work.AppendRemove(work.List{}, args.Path, keep)
+	room, err := msfs.MakeRoomFor(dir, num+1, len(parts)-1, manuscript.NumberWidth())
+	if err != nil {
+		return
+	}
+	workList = append(workList, room...)
+	for i, part := range parts {
+		path := filepath.Join(dir, partFilename(filepath.Base(args.Path), num, i, manuscript.NumberWidth()))
+		workList = work.AddFileWithContent(workList, path, string(frontMatter)+part)
+	}
+
+	if args.DryRun || args.PlanJson {
+		return work.Preview(workList, args.PlanJson, os.Stdout)
+	}
+
+	journal, err := msfs.Journal(manuscript.Path())
+	if err != nil {
+		return
+	}
+
+	err = work.Execute(workList, args.Force, journal)
+	if err != nil {
+		return
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package split
+
+import (
+	"gwcoffey/otis/fixture"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+const frontMatter = "---\nstatus: draft\n---\n"
+
+func TestSplit(t *testing.T) {
+	atLine := 8
+	tests := []struct {
+		name     string
+		args     Args
+		expected map[string]string
+	}{
+		{"at the first marker", Args{AtMarker: true}, map[string]string{
+			"01-storm.md":   "The storm broke at noon.\n",
+			"02-storm-2.md": "By evening the streets were flooded.\n\n#\n\nNobody slept.\n",
+			"03-calm.md":    "The morning was still.\n",
+		}},
+		{"at every marker", Args{AllMarkers: true}, map[string]string{
+			"01-storm.md":   "The storm broke at noon.\n",
+			"02-storm-2.md": "By evening the streets were flooded.\n",
+			"03-storm-3.md": "Nobody slept.\n",
+			"04-calm.md":    "The morning was still.\n",
+		}},
+		// lines are counted from the top of the file, front matter and all
+		{"at a line", Args{AtLine: &atLine}, map[string]string{
+			"01-storm.md":   "The storm broke at noon.\n\n#\n",
+			"02-storm-2.md": "By evening the streets were flooded.\n\n#\n\nNobody slept.\n",
+			"03-calm.md":    "The morning was still.\n",
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			root := fixture.Project(t, "scenes")
+			dir := filepath.Join(root, "manuscript", "00-beginning")
+			test.args.Path = filepath.Join(dir, "01-storm.md")
+			test.args.Force = true
+			if err := Split(&test.args); err != nil {
+				t.Fatal(err)
+			}
+
+			expected := []string{"00-arrival.md", "chapter.yml"}
+			for name := range test.expected {
+				expected = append(expected, name)
+			}
+			slices.Sort(expected)
+			if files := fixture.Files(t, dir); !slices.Equal(files, expected) {
+				t.Fatalf("expected %v, got %v", expected, files)
+			}
+
+			// every part keeps the scene's front matter
+			for name, text := range test.expected {
+				if name != "03-calm.md" && name != "04-calm.md" {
+					text = frontMatter + text
+				}
+				if content := fixture.Read(t, filepath.Join(dir, name)); content != text {
+					t.Errorf("expected %s to be %q, got %q", name, text, content)
+				}
+			}
+		})
+	}
+}
+
+func TestSplitWithoutSplitPoint(t *testing.T) {
+	root := fixture.Project(t, "scenes")
+	dir := filepath.Join(root, "manuscript", "00-beginning")
+	inFrontMatter, firstLine := 2, 4
+
+	tests := []Args{
+		{Path: filepath.Join(dir, "01-storm.md")},
+		{Path: filepath.Join(dir, "01-storm.md"), AtMarker: true, AllMarkers: true},
+		{Path: filepath.Join(dir, "01-storm.md"), AtLine: &inFrontMatter},
+		{Path: filepath.Join(dir, "01-storm.md"), AtLine: &firstLine},
+		{Path: filepath.Join(dir, "02-calm.md"), AllMarkers: true},
+	}
+	for _, args := range tests {
+		args.Force = true
+		if err := Split(&args); err == nil {
+			t.Errorf("expected %v to fail", args)
+		}
+	}
+
+	expected := []string{"00-arrival.md", "01-storm.md", "02-calm.md", "chapter.yml"}
+	if files := fixture.Files(t, dir); !slices.Equal(files, expected) {
+		t.Errorf("expected nothing to change, got %v", files)
+	}
+}
@@ -1,17 +1,21 @@
 package touch
 
 import (
+	"gwcoffey/otis/cli"
 	"gwcoffey/otis/ms"
 	"gwcoffey/otis/msfs"
 	"gwcoffey/otis/work"
+	"os"
 	"path/filepath"
 )
 
 type Args struct {
-	Path  string `arg:"positional,required" help:"where to put the scene"`
-	Name  string `arg:"positional,required" help:"the name of the scene"`
-	At    *int   `arg:"--at,-a" help:"the scene number at which to insert"`
-	Force bool   `arg:"--force,-f" help:"move other files around without confirmation"`
+	Path     string `arg:"positional,required" help:"where to put the scene"`
+	Name     string `arg:"positional,required" help:"the name of the scene"`
+	At       *int   `arg:"--at,-a" help:"the scene number at which to insert"`
+	Force    bool   `arg:"--force,-f" help:"move other files around without confirmation"`
+	DryRun   bool   `arg:"--dry-run,-n" help:"show what would change without changing anything"`
+	PlanJson bool   `arg:"--plan-json" help:"print what would change as JSON (for otis apply) without changing anything"`
 }
 
 func targetSceneNumber(args *Args) (num int, err error) {
@@ -24,10 +28,13 @@ func targetSceneNumber(args *Args) (num int, err error) {
 }
 
 func Touch(args *Args) (err error) {
-	_, err = ms.LoadContaining(args.Path)
+	manuscript, err := ms.LoadContaining(args.Path)
 	if err != nil {
 		return
 	}
+	for _, warning := range manuscript.Warnings() {
+		cli.Warn("%s: %s", warning.Path, warning.Message)
+	}
 
 	// target either the end of the scene list or the provided scene number
 	sceneNumber, err := targetSceneNumber(args)
@@ -36,10 +43,19 @@ func Touch(args *Args) (err error) {
 	}
 
 	// make a work list for this add
-	workList, err := msfs.MakeRoom(args.Path, sceneNumber)
-	workList = work.AddFile(workList, filepath.Join(args.Path, msfs.MakeFilename(args.Name, sceneNumber)))
+	workList, err := msfs.MakeRoom(args.Path, sceneNumber, manuscript.NumberWidth())
+	workList = work.AddFile(workList, filepath.Join(args.Path, msfs.MakeFilename(args.Name, sceneNumber, manuscript.NumberWidth())))
+
+	if args.DryRun || args.PlanJson {
+		return work.Preview(workList, args.PlanJson, os.Stdout)
+	}
+
+	journal, err := msfs.Journal(manuscript.Path())
+	if err != nil {
+		return
+	}
 
-	err = work.Execute(workList, args.Force)
+	err = work.Execute(workList, args.Force, journal)
 	if err != nil {
 		return
 	}
@@ -0,0 +1,66 @@
+package undo
+
+import (
+	"fmt"
+	"gwcoffey/otis/ms"
+	"gwcoffey/otis/msfs"
+	"gwcoffey/otis/oerr"
+	"gwcoffey/otis/work"
+)
+
+type Args struct {
+	Steps int  `arg:"positional" help:"how many operations to undo" default:"1"`
+	List  bool `arg:"--list,-l" help:"list the operations that can be undone instead"`
+	Force bool `arg:"--force,-f" help:"undo without confirmation"`
+}
+
+func printJournal(entries []work.Entry) {
+	if len(entries) == 0 {
+		fmt.Println("nothing to undo")
+		return
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		fmt.Printf("%2d. %s  %s\n", len(entries)-i, entry.Time.Format("2006-01-02 15:04:05"), entry.Command)
+	}
+}
+
+func Undo(args *Args) (err error) {
+	manuscript, err := ms.LoadHere()
+	if err != nil {
+		return
+	}
+
+	journal, err := msfs.Journal(manuscript.Path())
+	if err != nil {
+		return
+	}
+
+	entries, err := journal.Entries()
+	if err != nil {
+		return
+	}
+
+	if args.List {
+		printJournal(entries)
+		return nil
+	}
+
+	if args.Steps < 1 || args.Steps > len(entries) {
+		return oerr.NothingToUndo(args.Steps, len(entries))
+	}
+
+	undone := entries[len(entries)-args.Steps:]
+	for i := len(undone) - 1; i >= 0; i-- {
+		fmt.Printf("undoing: %s\n", undone[i].Command)
+	}
+
+	// the undo itself isn't journaled; instead the undone operations leave the journal
+	inverse := journal.Inverse(undone)
+	ran, err := work.ExecuteConfirmed(inverse, args.Force, nil)
+	if err != nil || !ran {
+		return
+	}
+
+	return journal.Drop(args.Steps)
+}
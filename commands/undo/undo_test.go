@@ -0,0 +1,100 @@
+package undo
+
+import (
+	"gwcoffey/otis/commands/mv"
+	"gwcoffey/otis/fixture"
+	"gwcoffey/otis/msfs"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// moveScenes moves the last scene of the first chapter to the front, and then the first scene of
+// the second chapter to the end of the first, returning the folders it changed
+func moveScenes(t *testing.T, root string) (beginning string, middle string) {
+	beginning = filepath.Join(root, "manuscript", "00-beginning")
+	middle = filepath.Join(root, "manuscript", "01-middle")
+
+	at := 0
+	if err := mv.Mv(&mv.Args{Path: filepath.Join(beginning, "02-calm.md"), At: &at, Force: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := mv.Mv(&mv.Args{Path: filepath.Join(middle, "00-road.md"), TargetPath: &beginning, Force: true}); err != nil {
+		t.Fatal(err)
+	}
+	return
+}
+
+func journalLength(t *testing.T, root string) int {
+	journal, err := msfs.Journal(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, err := journal.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return len(entries)
+}
+
+func TestUndo(t *testing.T) {
+	root := fixture.Project(t, "scenes")
+	beginning, middle := moveScenes(t, root)
+	fixture.Chdir(t, root)
+
+	fixture.Stdout(t, func() {
+		if err := Undo(&Args{Steps: 1, Force: true}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	expected := []string{"00-calm.md", "01-arrival.md", "02-storm.md", "chapter.yml"}
+	if files := fixture.Files(t, beginning); !slices.Equal(files, expected) {
+		t.Errorf("expected %v, got %v", expected, files)
+	}
+	expected = []string{"00-road.md", "01-city.md", "chapter.yml"}
+	if files := fixture.Files(t, middle); !slices.Equal(files, expected) {
+		t.Errorf("expected %v, got %v", expected, files)
+	}
+	if length := journalLength(t, root); length != 1 {
+		t.Errorf("expected 1 entry left in the journal, got %d", length)
+	}
+
+	fixture.Stdout(t, func() {
+		if err := Undo(&Args{Steps: 1, Force: true}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	expected = []string{"00-arrival.md", "01-storm.md", "02-calm.md", "chapter.yml"}
+	if files := fixture.Files(t, beginning); !slices.Equal(files, expected) {
+		t.Errorf("expected %v, got %v", expected, files)
+	}
+	if length := journalLength(t, root); length != 0 {
+		t.Errorf("expected an empty journal, got %d entries", length)
+	}
+
+	if err := Undo(&Args{Steps: 1, Force: true}); err == nil {
+		t.Error("expected nothing to undo")
+	}
+}
+
+func TestUndoDeclined(t *testing.T) {
+	root := fixture.Project(t, "scenes")
+	beginning, _ := moveScenes(t, root)
+	fixture.Chdir(t, root)
+
+	// undoing two operations asks first
+	fixture.Stdin(t, "n\n")
+	fixture.Stdout(t, func() {
+		if err := Undo(&Args{Steps: 2}); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	expected := []string{"00-calm.md", "01-arrival.md", "02-storm.md", "03-road.md", "chapter.yml"}
+	if files := fixture.Files(t, beginning); !slices.Equal(files, expected) {
+		t.Errorf("expected nothing to change, got %v", files)
+	}
+	if length := journalLength(t, root); length != 2 {
+		t.Errorf("expected the journal to keep both entries, got %d", length)
+	}
+}
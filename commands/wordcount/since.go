@@ -0,0 +1,88 @@
+package wordcount
+
+import (
+	"gwcoffey/otis/git"
+	ms2 "gwcoffey/otis/ms"
+	"gwcoffey/otis/oerr"
+	"path/filepath"
+)
+
+// baseline holds the word counts of the manuscript at an earlier git revision, so each line of
+// the report can show how much it has changed; a nil baseline shows no changes
+type baseline struct {
+	manuscript ms2.Manuscript
+	// counts maps scene paths (relative to the project root) at the revision to their word counts
+	counts map[string]int
+	// renames maps the current path of each scene git sees as renamed (for instance by `otis mv`)
+	// to its path at the revision
+	renames map[string]string
+	// deleted is the word count at the revision of scenes that no longer exist
+	deleted int
+}
+
+func loadBaseline(m ms2.Manuscript, rev string) (base *baseline, err error) {
+	if !git.IsRepo(m.Path()) {
+		return nil, oerr.NotAGitRepo(m.Path())
+	}
+
+	counts, err := ms2.SceneWordCountsAt(m, rev)
+	if err != nil {
+		return
+	}
+	renames, err := git.Renames(m.Path(), rev, "manuscript")
+	if err != nil {
+		return
+	}
+
+	base = &baseline{manuscript: m, counts: counts, renames: renames}
+	remaining := map[string]bool{}
+	for _, scene := range m.Whole().Scenes() {
+		remaining[base.previousPath(scene)] = true
+	}
+	for path, count := range counts {
+		if !remaining[path] {
+			base.deleted += count
+		}
+	}
+	return
+}
+
+// previousPath returns the path of the scene at the revision, relative to the project root
+func (b *baseline) previousPath(scene ms2.Scene) string {
+	path, err := filepath.Rel(b.manuscript.Path(), scene.Path())
+	if err != nil {
+		return ""
+	}
+	path = filepath.ToSlash(path)
+	if old, renamed := b.renames[path]; renamed {
+		path = old
+	}
+	return path
+}
+
+// previousCount returns the word count of the scene at the revision (zero for new scenes)
+func (b *baseline) previousCount(scene ms2.Scene) int {
+	return b.counts[b.previousPath(scene)]
+}
+
+// scenesDelta returns the change in words across the given scenes, which now total count
+func (b *baseline) scenesDelta(scenes []ms2.Scene, count int) *int {
+	if b == nil {
+		return nil
+	}
+	delta := count
+	for _, scene := range scenes {
+		delta -= b.previousCount(scene)
+	}
+	return &delta
+}
+
+// manuscriptDelta returns the change in words for the manuscript, which now totals count; unless
+// the manuscript is filtered, that includes the words in scenes that have since been deleted
+func (b *baseline) manuscriptDelta(m ms2.Manuscript, count int) *int {
+	delta := b.scenesDelta(m.Scenes(), count)
+	if delta != nil && m.Whole() == m {
+		*delta -= b.deleted
+	}
+	return delta
+}
@@ -0,0 +1,103 @@
+package wordcount
+
+import (
+	"gwcoffey/otis/fixture"
+	"gwcoffey/otis/ms"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// changedRepo makes a git repository of the scenes project, then changes it without committing:
+// a scene is moved (as otis mv would, staged so git sees the rename), one is edited, one is
+// deleted, and a new one is added
+func changedRepo(t *testing.T) string {
+	root := fixture.Repo(t, "scenes")
+	manuscript := filepath.Join(root, "manuscript")
+
+	fixture.Git(t, root, "mv", "manuscript/01-middle/00-road.md", "manuscript/02-end/01-road.md")
+	fixture.Git(t, root, "rm", "--quiet", "manuscript/01-middle/01-city.md")
+	files := map[string]string{
+		"00-beginning/02-calm.md": "The morning was still. More rain.\n",
+		"02-end/02-night.md":      "Night fell over the city.\n",
+	}
+	for name, text := range files {
+		if err := os.WriteFile(filepath.Join(manuscript, name), []byte(text), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestLoadBaseline(t *testing.T) {
+	m, err := ms.Load(changedRepo(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	base, err := loadBaseline(m, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the moved scene is compared with itself at the revision, and the new scene with nothing
+	expected := map[string]int{
+		"00-arrival.md": 0,
+		"01-storm.md":   0,
+		"02-calm.md":    2,
+		"01-road.md":    0,
+		"00-home.md":    0,
+		"02-night.md":   5,
+	}
+	for _, scene := range m.Scenes() {
+		count, err := sceneWordCount(scene)
+		if err != nil {
+			t.Fatal(err)
+		}
+		name := filepath.Base(scene.Path())
+		if delta := base.scenesDelta([]ms.Scene{scene}, count); *delta != expected[name] {
+			t.Errorf("expected %s to change by %d, got %d", name, expected[name], *delta)
+		}
+	}
+
+	// the deleted scene's words count against the manuscript
+	if base.deleted != 4 {
+		t.Errorf("expected 4 deleted words, got %d", base.deleted)
+	}
+	count, err := ms.WordCount(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if delta := base.manuscriptDelta(m, count); *delta != 3 {
+		t.Errorf("expected the manuscript to change by 3, got %d", *delta)
+	}
+}
+
+func TestWordCountSince(t *testing.T) {
+	root := changedRepo(t)
+	since := "HEAD"
+
+	out := fixture.Stdout(t, func() {
+		if err := WordCount(&Args{ProjectPath: &root, Since: &since}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	lines := strings.Split(out, "\n")
+	if fields := strings.Fields(lines[0]); len(fields) < 2 || fields[len(fields)-2] != "39" || !strings.HasPrefix(fields[len(fields)-1], "+3") {
+		t.Errorf("expected 39 words, up 3, got\n%s", out)
+	}
+}
+
+func TestSinceNeedsGit(t *testing.T) {
+	root := fixture.Project(t, "scenes")
+	since := "HEAD"
+	if err := WordCount(&Args{ProjectPath: &root, Since: &since}); err == nil {
+		t.Error("expected counting since a revision outside a git repository to fail")
+	}
+
+	root = fixture.Repo(t, "scenes")
+	since = "no-such-revision"
+	if err := WordCount(&Args{ProjectPath: &root, Since: &since}); err == nil {
+		t.Error("expected an unknown revision to fail")
+	}
+}
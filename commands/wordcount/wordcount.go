@@ -13,8 +13,11 @@ const maxWidth = 40
 const indentSize = "  "
 
 type Args struct {
-	ProjectPath *string `arg:"positional" help:"path to the otis project"`
-	ByChapter   bool    `arg:"--chapter,-c" help:"count by chapter rather than by folder"`
+	ProjectPath   *string  `arg:"positional" help:"path to the otis project"`
+	ByChapter     bool     `arg:"--chapter,-c" help:"count by chapter rather than by folder"`
+	Where         []string `arg:"--where,separate" help:"count only scenes whose metadata matches, like pov=Anna (can be repeated)"`
+	ExcludeStatus []string `arg:"--exclude-status,separate" help:"leave out scenes with this status (can be repeated)"`
+	Since         *string  `arg:"--since,-s" help:"show the change in words since this git revision"`
 }
 
 type printBy int
@@ -41,24 +44,24 @@ func truncate(str string) string {
 	return result
 }
 
-func printManuscript(m ms2.Manuscript, by printBy) (err error) {
+func printManuscript(m ms2.Manuscript, by printBy, base *baseline) (err error) {
 	count, err := ms2.WordCount(m)
 	if err != nil {
 		return
 	}
-	printLine(truncate(m.Title()), count, true)
+	printLine(truncate(m.Title()), count, base.manuscriptDelta(m, count), true)
 
 	switch by {
 	case byFolder:
 		for _, folder := range m.Folders() {
-			err = printFolder(folder, indentSize)
+			err = printFolder(folder, indentSize, base)
 			if err != nil {
 				return
 			}
 		}
 	case byChapter:
 		for _, chapter := range m.Chapters() {
-			err = printChapter(chapter, indentSize)
+			err = printChapter(chapter, indentSize, base)
 			if err != nil {
 				return
 			}
@@ -67,7 +70,37 @@ func printManuscript(m ms2.Manuscript, by printBy) (err error) {
 	return
 }
 
-func printFolder(folder ms2.Folder, indent string) (err error) {
+// printSections prints the front or back matter sections, which are counted apart from the
+// manuscript
+func printSections(heading string, sections []ms2.Section) (err error) {
+	if len(sections) == 0 {
+		return
+	}
+
+	counts := make([]int, len(sections))
+	total := 0
+	for i, section := range sections {
+		var text string
+		text, err = section.Text()
+		if err != nil {
+			return
+		}
+		counts[i] = len(strings.Fields(text))
+		total += counts[i]
+	}
+
+	printLine(truncate(heading), total, nil, true)
+	for i, section := range sections {
+		label := section.Title()
+		if label == "" {
+			label = section.PrettyFileName()
+		}
+		printLine(truncate(indentSize+label), counts[i], nil, false)
+	}
+	return
+}
+
+func printFolder(folder ms2.Folder, indent string, base *baseline) (err error) {
 	fcount, err := folderWordCount(folder)
 	if err != nil {
 		return
@@ -75,17 +108,17 @@ func printFolder(folder ms2.Folder, indent string) (err error) {
 
 	label := fmt.Sprintf("%02d. %s", folder.Number()+1, folder.PrettyFileName())
 
-	printLine(truncate(indent+label), fcount, true)
+	printLine(truncate(indent+label), fcount, base.scenesDelta(folder.AllScenes(), fcount), true)
 
 	for _, scene := range folder.Scenes() {
-		err = printScene(scene, indent+indentSize)
+		err = printScene(scene, indent+indentSize, base)
 		if err != nil {
 			return
 		}
 	}
 
 	for _, child := range folder.Folders() {
-		err = printFolder(child, indent+indentSize)
+		err = printFolder(child, indent+indentSize, base)
 		if err != nil {
 			return
 		}
@@ -106,7 +139,7 @@ func folderWordCount(folder ms2.Folder) (count int, err error) {
 	return
 }
 
-func printChapter(chapter ms2.Chapter, indent string) (err error) {
+func printChapter(chapter ms2.Chapter, indent string, base *baseline) (err error) {
 	ccount, err := chapterWordCount(chapter)
 	if err != nil {
 		return
@@ -119,7 +152,7 @@ func printChapter(chapter ms2.Chapter, indent string) (err error) {
 		label = fmt.Sprintf("    %s", chapter.Title())
 	}
 
-	printLine(truncate(indent+label), ccount, false)
+	printLine(truncate(indent+label), ccount, base.scenesDelta(chapter.Scenes(), ccount), false)
 	return
 }
 
@@ -135,23 +168,30 @@ func chapterWordCount(chapter ms2.Chapter) (count int, err error) {
 	return
 }
 
-func printScene(scene ms2.Scene, indent string) (err error) {
+func printScene(scene ms2.Scene, indent string, base *baseline) (err error) {
 	scount, err := sceneWordCount(scene)
 	if err != nil {
 		return
 	}
 	label := fmt.Sprintf("%02d. %s", scene.Number()+1, scene.PrettyFileName())
-	printLine(truncate(indent+label), scount, false)
+	printLine(truncate(indent+label), scount, base.scenesDelta([]ms2.Scene{scene}, scount), false)
 	return
 }
 
-func printLine(label string, count int, emphasize bool) {
+// printLine prints a label and its word count, followed by the change in words if there is one
+func printLine(label string, count int, delta *int, emphasize bool) {
 	out := message.NewPrinter(language.English)
-	format := fmt.Sprintf("%%-%d.%ds : %%7d\n", maxWidth, maxWidth)
+	format := fmt.Sprintf("%%-%d.%ds : %%7d", maxWidth, maxWidth)
+	args := []any{label, count}
+	if delta != nil {
+		format += " %+7d"
+		args = append(args, *delta)
+	}
+	format += "\n"
 	if emphasize {
 		format = fmt.Sprintf("\033[94m%s\033[0m", format)
 	}
-	_, err := out.Printf(format, label, count)
+	_, err := out.Printf(format, args...)
 	if err != nil {
 		panic(err)
 	}
@@ -169,12 +209,33 @@ func WordCount(args *Args) (err error) {
 		return
 	}
 
+	manuscript, err = ms2.Select(manuscript, args.Where, args.ExcludeStatus)
+	if err != nil {
+		return
+	}
+
 	by := byFolder
 	if args.ByChapter {
 		by = byChapter
 	}
 
-	err = printManuscript(manuscript, by)
+	var base *baseline
+	if args.Since != nil {
+		base, err = loadBaseline(manuscript, *args.Since)
+		if err != nil {
+			return
+		}
+	}
+
+	err = printManuscript(manuscript, by, base)
+	if err != nil {
+		return
+	}
+	err = printSections("Front Matter", manuscript.FrontMatter())
+	if err != nil {
+		return
+	}
+	err = printSections("Back Matter", manuscript.BackMatter())
 	if err != nil {
 		return
 	}
@@ -0,0 +1,171 @@
+// Package fixture sets up the example projects in testdata (on their own or in git repositories)
+// for tests, and captures what commands print
+package fixture
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"testing"
+)
+
+// projects is the folder holding the example projects
+var projects = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "..", "testdata", "projects")
+}()
+
+// Path returns the path of the named example project, for tests that only read it
+func Path(name string) string {
+	return filepath.Join(projects, name)
+}
+
+// Project copies the named example project into a temporary directory, so a test can change it,
+// and returns the path of the copy
+func Project(t *testing.T, name string) string {
+	t.Helper()
+	src := Path(name)
+	root := filepath.Join(t.TempDir(), filepath.Base(name))
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return os.MkdirAll(filepath.Join(root, rel), 0755)
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(root, rel), content, 0644)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+// Repo copies the named example project into a new git repository and commits it
+func Repo(t *testing.T, name string) string {
+	t.Helper()
+	root := Project(t, name)
+	Git(t, root, "init", "--quiet")
+	Git(t, root, "add", ".")
+	Git(t, root, "commit", "--quiet", "--message", "start")
+	return root
+}
+
+// Git runs git in dir as a test author, ignoring the user's git configuration, and returns what
+// it prints
+func Git(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Env = append(os.Environ(),
+		"GIT_CONFIG_GLOBAL="+os.DevNull,
+		"GIT_CONFIG_NOSYSTEM=1",
+		"GIT_AUTHOR_NAME=Wendy Writer",
+		"GIT_AUTHOR_EMAIL=wendy@example.com",
+		"GIT_COMMITTER_NAME=Wendy Writer",
+		"GIT_COMMITTER_EMAIL=wendy@example.com",
+	)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %s: %s", args, err, out)
+	}
+	return string(out)
+}
+
+// Files returns the files under dir (slash separated and relative to dir), in order
+func Files(t *testing.T, dir string) (files []string) {
+	t.Helper()
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		files = append(files, filepath.ToSlash(rel))
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(files)
+	return
+}
+
+// Read returns the content of a file
+func Read(t *testing.T, path string) string {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content)
+}
+
+// Chdir changes the working directory for the rest of the test, for commands that work on the
+// project they are run in
+func Chdir(t *testing.T, dir string) {
+	t.Helper()
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(cwd) })
+}
+
+// Stdin makes input what the rest of the test reads from standard input, to answer prompts
+func Stdin(t *testing.T, input string) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = io.WriteString(w, input); err != nil {
+		t.Fatal(err)
+	}
+	_ = w.Close()
+
+	stdin := os.Stdin
+	os.Stdin = r
+	t.Cleanup(func() {
+		os.Stdin = stdin
+		_ = r.Close()
+	})
+}
+
+// Stdout runs f and returns what it prints to standard output
+func Stdout(t *testing.T, f func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := make(chan string)
+	go func() {
+		content, _ := io.ReadAll(r)
+		out <- string(content)
+	}()
+
+	stdout := os.Stdout
+	os.Stdout = w
+	func() {
+		defer func() {
+			os.Stdout = stdout
+			_ = w.Close()
+		}()
+		f()
+	}()
+	return <-out
+}
@@ -0,0 +1,237 @@
+// Package git reads manuscript history from a git repository by running the `git` command
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Commit is a commit in the history of a repository
+type Commit struct {
+	Hash string
+	Time time.Time
+}
+
+// run runs git in the given directory and returns its standard output
+func run(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s: %s", strings.Join(args, " "), strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// IsRepo reports whether the directory is inside a git working tree
+func IsRepo(dir string) bool {
+	out, err := run(dir, "rev-parse", "--is-inside-work-tree")
+	return err == nil && strings.TrimSpace(string(out)) == "true"
+}
+
+// Log returns the commits that touched the given path (relative to dir), oldest first
+func Log(dir string, path string) (commits []Commit, err error) {
+	out, err := run(dir, "log", "--reverse", "--format=%H %ct", "--", path)
+	if err != nil {
+		return
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		hash, stamp, found := strings.Cut(line, " ")
+		if !found {
+			continue
+		}
+		seconds, perr := strconv.ParseInt(stamp, 10, 64)
+		if perr != nil {
+			return nil, perr
+		}
+		commits = append(commits, Commit{Hash: hash, Time: time.Unix(seconds, 0)})
+	}
+	return
+}
+
+// Files returns the blob hash of every file under path (relative to dir) at the given revision,
+// keyed by the file's path relative to dir
+func Files(dir string, rev string, path string) (files map[string]string, err error) {
+	out, err := run(dir, "ls-tree", "-r", "-z", rev, "--", path)
+	if err != nil {
+		return
+	}
+
+	files = map[string]string{}
+	for _, entry := range strings.Split(string(out), "\x00") {
+		// each entry is "<mode> <type> <hash>\t<path>"
+		info, name, found := strings.Cut(entry, "\t")
+		fields := strings.Fields(info)
+		if !found || len(fields) != 3 || fields[1] != "blob" {
+			continue
+		}
+		files[name] = fields[2]
+	}
+	return
+}
+
+// ReadBlobs returns the content of each blob, keyed by hash; it reads them all through a single
+// `git cat-file` process
+func ReadBlobs(dir string, hashes []string) (blobs map[string][]byte, err error) {
+	blobs = map[string][]byte{}
+	if len(hashes) == 0 {
+		return
+	}
+
+	cmd := exec.Command("git", "-C", dir, "cat-file", "--batch")
+	cmd.Stdin = strings.NewReader(strings.Join(hashes, "\n") + "\n")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return
+	}
+	if err = cmd.Start(); err != nil {
+		return
+	}
+
+	reader := bufio.NewReader(stdout)
+	for range hashes {
+		// each blob is "<hash> <type> <size>\n<content>\n"
+		var header string
+		header, err = reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		fields := strings.Fields(header)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("git cat-file: unexpected output %q", strings.TrimSpace(header))
+		}
+		var size int
+		size, err = strconv.Atoi(fields[2])
+		if err != nil {
+			return
+		}
+		content := make([]byte, size+1)
+		if _, err = io.ReadFull(reader, content); err != nil {
+			return
+		}
+		blobs[fields[0]] = content[:size]
+	}
+
+	err = cmd.Wait()
+	return
+}
+
+// Renames returns the files under path (relative to dir) that git sees as renamed between the
+// revision and the working tree, mapping each new path to its path at the revision
+func Renames(dir string, rev string, path string) (renames map[string]string, err error) {
+	out, err := run(dir, "diff", "-M", "-z", "--name-status", "--relative", rev, "--", path)
+	if err != nil {
+		return
+	}
+
+	renames = map[string]string{}
+	// entries are "<status>\0<path>\0", except renames and copies which have two paths
+	fields := strings.Split(string(out), "\x00")
+	for i := 0; i < len(fields); i++ {
+		status := fields[i]
+		if strings.HasPrefix(status, "R") || strings.HasPrefix(status, "C") {
+			if i+2 >= len(fields) {
+				break
+			}
+			if strings.HasPrefix(status, "R") {
+				renames[fields[i+2]] = fields[i+1]
+			}
+			i += 2
+		} else if status != "" {
+			i++
+		}
+	}
+	return
+}
+
+// TopLevel returns the root directory of the work tree containing dir
+func TopLevel(dir string) (string, error) {
+	out, err := run(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// IndexEntry is a file in the git index; Path is relative to the top of the work tree
+type IndexEntry struct {
+	Mode string
+	Hash string
+	Path string
+}
+
+// Tracked returns the index entries for the tracked files at or under the given paths, which
+// must be absolute or relative to top
+func Tracked(top string, paths []string) (entries []IndexEntry, err error) {
+	out, err := run(top, append([]string{"ls-files", "-s", "-z", "--full-name", "--"}, paths...)...)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range strings.Split(string(out), "\x00") {
+		// each entry is "<mode> <hash> <stage>\t<path>"
+		info, path, found := strings.Cut(entry, "\t")
+		fields := strings.Fields(info)
+		if !found || len(fields) != 3 || fields[2] != "0" {
+			continue // skip unmerged files
+		}
+		entries = append(entries, IndexEntry{Mode: fields[0], Hash: fields[1], Path: path})
+	}
+	return
+}
+
+// UpdateIndex adds the entries to the index and removes the given paths from it (paths are
+// relative to top); moving an entry this way is what `git mv` does, so git sees a rename
+func UpdateIndex(top string, add []IndexEntry, remove []string) error {
+	var input strings.Builder
+	for _, path := range remove {
+		input.WriteString("0 0000000000000000000000000000000000000000\t" + path + "\x00")
+	}
+	for _, entry := range add {
+		input.WriteString(entry.Mode + " " + entry.Hash + "\t" + entry.Path + "\x00")
+	}
+
+	cmd := exec.Command("git", "-C", top, "update-index", "-z", "--index-info")
+	cmd.Stdin = strings.NewReader(input.String())
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("git update-index: %s", strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// Ignored returns which of the paths (relative to top) git ignores
+func Ignored(top string, paths []string) (ignored map[string]bool, err error) {
+	ignored = map[string]bool{}
+	if len(paths) == 0 {
+		return
+	}
+
+	cmd := exec.Command("git", "-C", top, "check-ignore", "-z", "--stdin")
+	cmd.Stdin = strings.NewReader(strings.Join(paths, "\x00") + "\x00")
+	out, err := cmd.Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		// none of the paths are ignored
+		return ignored, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("git check-ignore: %w", err)
+	}
+
+	for _, path := range strings.Split(string(out), "\x00") {
+		if path != "" {
+			ignored[path] = true
+		}
+	}
+	return
+}
@@ -0,0 +1,60 @@
+package git
+
+import (
+	"gwcoffey/otis/fixture"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestUpdateIndexAndRenames(t *testing.T) {
+	root := fixture.Repo(t, "scenes")
+	from, to := "manuscript/02-end/00-home.md", "manuscript/02-end/01-home.md"
+	if err := os.Rename(filepath.Join(root, from), filepath.Join(root, to)); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := Tracked(root, []string{"manuscript/02-end"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Path != from {
+		t.Fatalf("expected only %s to be tracked, got %v", from, entries)
+	}
+
+	// moving the entry in the index is what git mv does
+	moved := IndexEntry{Mode: entries[0].Mode, Hash: entries[0].Hash, Path: to}
+	if err = UpdateIndex(root, []IndexEntry{moved}, []string{from}); err != nil {
+		t.Fatal(err)
+	}
+	if status := fixture.Git(t, root, "status", "--porcelain"); status != "R  "+from+" -> "+to+"\n" {
+		t.Errorf("expected a staged rename, got %q", status)
+	}
+
+	renames, err := Renames(root, "HEAD", "manuscript")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(renames) != 1 || renames[to] != from {
+		t.Errorf("expected %s to be renamed from %s, got %v", to, from, renames)
+	}
+}
+
+func TestIgnored(t *testing.T) {
+	root := fixture.Repo(t, "scenes")
+	if err := os.WriteFile(filepath.Join(root, ".gitignore"), []byte(".build/\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ignored, err := Ignored(root, []string{".build/trash/00-home.md", "manuscript/02-end/00-home.md"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ignored) != 1 || !ignored[".build/trash/00-home.md"] {
+		t.Errorf("expected only the build directory to be ignored, got %v", ignored)
+	}
+
+	if ignored, err = Ignored(root, []string{"manuscript/02-end/00-home.md"}); err != nil || len(ignored) != 0 {
+		t.Errorf("expected nothing to be ignored, got %v %v", ignored, err)
+	}
+}
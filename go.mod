@@ -2,11 +2,11 @@ module gwcoffey/otis
 
 go 1.21
 
-require golang.org/x/text v0.12.0
-
 require (
-	github.com/alexflint/go-arg v1.4.3 // indirect
-	github.com/alexflint/go-scalar v1.2.0 // indirect
-	github.com/go-yaml/yaml v2.1.0+incompatible // indirect
-	github.com/gomarkdown/markdown v0.0.0-20230716120725-531d2d74bc12 // indirect
+	github.com/alexflint/go-arg v1.4.3
+	github.com/go-yaml/yaml v2.1.0+incompatible
+	github.com/gomarkdown/markdown v0.0.0-20230716120725-531d2d74bc12
+	golang.org/x/text v0.12.0
 )
+
+require github.com/alexflint/go-scalar v1.2.0 // indirect
@@ -4,11 +4,20 @@ import (
 	"errors"
 	"fmt"
 	"github.com/alexflint/go-arg"
+	"gwcoffey/otis/commands/apply"
+	"gwcoffey/otis/commands/chapter"
+	"gwcoffey/otis/commands/check"
 	"gwcoffey/otis/commands/compile"
 	"gwcoffey/otis/commands/initcmd"
+	"gwcoffey/otis/commands/join"
 	"gwcoffey/otis/commands/mkdir"
 	"gwcoffey/otis/commands/mv"
+	"gwcoffey/otis/commands/progress"
+	"gwcoffey/otis/commands/renumber"
+	"gwcoffey/otis/commands/rm"
+	"gwcoffey/otis/commands/split"
 	"gwcoffey/otis/commands/touch"
+	"gwcoffey/otis/commands/undo"
 	"gwcoffey/otis/commands/wordcount"
 	"gwcoffey/otis/oerr"
 	"os"
@@ -19,8 +28,17 @@ var args struct {
 	Touch     *touch.Args     `arg:"subcommand:touch" help:"add a new scene"`
 	MkDir     *mkdir.Args     `arg:"subcommand:mkdir" help:"add a new folder"`
 	Move      *mv.Args        `arg:"subcommand:mv" help:"move a scene or folder"`
+	Remove    *rm.Args        `arg:"subcommand:rm" help:"remove a scene or folder"`
+	Chapter   *chapter.Args   `arg:"subcommand:chapter" help:"add, remove, move, or list chapters"`
+	Split     *split.Args     `arg:"subcommand:split" help:"split a scene in two (or more)"`
+	Join      *join.Args      `arg:"subcommand:join" help:"join scenes into one"`
+	Renumber  *renumber.Args  `arg:"subcommand:renumber" help:"renumber scenes and folders without gaps or duplicates"`
+	Undo      *undo.Args      `arg:"subcommand:undo" help:"undo the last change to the manuscript's files"`
+	Apply     *apply.Args     `arg:"subcommand:apply" help:"make the changes in a saved plan"`
 	WordCount *wordcount.Args `arg:"subcommand:wc" help:"count words in your manuscript"`
 	Compile   *compile.Args   `arg:"subcommand:compile" help:"compile the manuscript for submission"`
+	Progress  *progress.Args  `arg:"subcommand:progress" help:"record today's word count and show progress toward your goal"`
+	Check     *check.Args     `arg:"subcommand:check" help:"look for problems in the manuscript's structure"`
 }
 
 func reportErrorAndExit(err error) {
@@ -53,12 +71,30 @@ func main() {
 		err = wordcount.WordCount(args.WordCount)
 	case args.Compile != nil:
 		err = compile.Compile(args.Compile)
+	case args.Progress != nil:
+		err = progress.Progress(args.Progress)
+	case args.Check != nil:
+		err = check.Check(args.Check)
 	case args.Touch != nil:
 		err = touch.Touch(args.Touch)
 	case args.MkDir != nil:
 		err = mkdir.MkDir(args.MkDir)
 	case args.Move != nil:
 		err = mv.Mv(args.Move)
+	case args.Remove != nil:
+		err = rm.Rm(args.Remove)
+	case args.Chapter != nil:
+		err = chapter.Chapter(args.Chapter)
+	case args.Split != nil:
+		err = split.Split(args.Split)
+	case args.Join != nil:
+		err = join.Join(args.Join)
+	case args.Renumber != nil:
+		err = renumber.Renumber(args.Renumber)
+	case args.Undo != nil:
+		err = undo.Undo(args.Undo)
+	case args.Apply != nil:
+		err = apply.Apply(args.Apply)
 	}
 
 	if err != nil {
@@ -1,6 +1,35 @@
 package ms
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"gwcoffey/otis/text"
+	"strings"
+)
+
+// NumberStyle controls how chapter numbers are written in headings
+type NumberStyle string
+
+const (
+	// NumbersArabic writes "Chapter 12"
+	NumbersArabic NumberStyle = "arabic"
+	// NumbersRoman writes "Chapter XII"
+	NumbersRoman NumberStyle = "roman"
+	// NumbersWords writes "Chapter Twelve"
+	NumbersWords NumberStyle = "words"
+)
+
+// HeadingStyle controls what appears at the top of each chapter
+type HeadingStyle string
+
+const (
+	// HeadingBoth shows "Chapter 3" above the chapter title
+	HeadingBoth HeadingStyle = "both"
+	// HeadingNumber shows just "Chapter 3" (unnumbered chapters still show their title)
+	HeadingNumber HeadingStyle = "number"
+	// HeadingTitle shows just the chapter title
+	HeadingTitle HeadingStyle = "title"
+)
 
 type chapter struct {
 	node       *node
@@ -12,7 +41,13 @@ type Chapter interface {
 	fmt.Stringer
 	Scenes() []Scene
 	Title() string
+	Subtitle() string
+	Epigraph() string
+	EpigraphAttribution() string
 	Number() *int
+	Label() string
+	Heading(style HeadingStyle) (label string, title string)
+	Path() string
 }
 
 func (c *chapter) String() string {
@@ -32,8 +67,8 @@ func (c *chapter) Scenes() (scenes []Scene) {
 		if capturing {
 			if node.chapterMeta != nil && node.chapterMeta != c.node.chapterMeta {
 				capturing = false
-			} else if !node.isDir {
-				scenes = append(scenes, &scene{node: node, chapter: c})
+			} else if !node.isDir && c.manuscript.includes(node) {
+				scenes = append(scenes, &scene{node: node, chapter: c, manuscript: c.manuscript})
 			}
 		}
 	})
@@ -45,10 +80,87 @@ func (c *chapter) Title() string {
 	return c.node.chapterMeta.Title
 }
 
+// Subtitle returns the line under the chapter title, if the chapter has one
+func (c *chapter) Subtitle() string {
+	return c.node.chapterMeta.Subtitle
+}
+
+// Epigraph returns the quotation at the start of the chapter, if it has one; line breaks in it are
+// kept, for verse
+func (c *chapter) Epigraph() string {
+	return strings.TrimSpace(c.node.chapterMeta.Epigraph)
+}
+
+// EpigraphAttribution returns who the epigraph is from, if anyone
+func (c *chapter) EpigraphAttribution() string {
+	return c.node.chapterMeta.EpigraphAttribution
+}
+
 func (c *chapter) Number() *int {
 	return c.number
 }
 
+// Label returns the label of a numbered chapter, like "Chapter 12", with the number written the way
+// otis.yml says; it is empty for an unnumbered chapter
+func (c *chapter) Label() string {
+	if c.number == nil {
+		return ""
+	}
+	switch c.manuscript.ChapterNumbers() {
+	case NumbersRoman:
+		return "Chapter " + text.ToRoman(*c.number)
+	case NumbersWords:
+		return "Chapter " + text.NumberToWords(*c.number)
+	}
+	return fmt.Sprintf("Chapter %d", *c.number)
+}
+
+// Heading returns the two lines of the chapter's heading: the label and the title. Either may be
+// empty, depending on the style and whether the chapter is numbered. Every compiler uses it, so
+// headings read the same in every format.
+func (c *chapter) Heading(style HeadingStyle) (label string, title string) {
+	if style != HeadingTitle {
+		label = c.Label()
+	}
+	if label == "" || style != HeadingNumber {
+		title = c.Title()
+	}
+	return
+}
+
 func (c *chapter) Path() string {
 	return c.node.path
 }
+
+// CheckChapterFolders checks that the manuscript would still start with a chapter, and (if it has
+// parts) that the first chapter would still start the first part, if the given folders (absolute
+// paths) were its chapter folders; it is used to check a change to the chapters before making it
+func CheckChapterFolders(m Manuscript, folders []string) error {
+	isChapter := map[string]bool{}
+	for _, folder := range folders {
+		isChapter[folder] = true
+	}
+
+	inChapter, loose := false, false
+	sawPart, sawChapter, looseChapter := false, false, false
+	m.(*manuscript).node.walk(func(n *node) {
+		if n.isDir && n.partMeta != nil {
+			sawPart = true
+		}
+		if n.isDir && isChapter[n.path] {
+			inChapter, sawChapter = true, true
+			looseChapter = looseChapter || !sawPart
+		} else if !n.isDir && !inChapter {
+			loose = true
+		}
+	})
+	switch {
+	case sawChapter && loose:
+		return errors.New(fmt.Sprintf("manuscript %s would have scenes before the first chapter", m.Path()))
+	case sawPart && !sawChapter:
+		return errors.New(fmt.Sprintf("manuscript %s would have parts but no chapters", m.Path()))
+	case sawPart && looseChapter:
+		return errors.New(fmt.Sprintf("manuscript %s would have chapters before the first part", m.Path()))
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package ms
+
+import (
+	"gwcoffey/otis/fixture"
+	"testing"
+)
+
+func TestChapterHeading(t *testing.T) {
+	m, err := Load(fixture.Path("headings"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []struct {
+		style HeadingStyle
+		label string
+		title string
+	}{
+		{HeadingNumber, "", "Prologue"},
+		{HeadingBoth, "Chapter I", "Start"},
+		{HeadingNumber, "Chapter X", ""},
+		{HeadingTitle, "", "More"},
+	}
+	chapters := m.Chapters()
+	if len(chapters) != len(expected) {
+		t.Fatalf("expected %d chapters, got %d", len(expected), len(chapters))
+	}
+	for i, e := range expected {
+		label, title := chapters[i].Heading(e.style)
+		if label != e.label || title != e.title {
+			t.Errorf("expected %q %q, got %q %q", e.label, e.title, label, title)
+		}
+	}
+	if label := chapters[3].Label(); label != "Chapter XI" {
+		t.Errorf("expected Chapter XI, got %q", label)
+	}
+}
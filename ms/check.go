@@ -0,0 +1,316 @@
+package ms
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-yaml/yaml"
+	"gwcoffey/otis/msfs"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+type Severity string
+
+const (
+	// SeverityError is a problem that stops otis from loading the manuscript
+	SeverityError Severity = "error"
+	// SeverityWarning is a problem otis can live with, but probably isn't what you meant
+	SeverityWarning Severity = "warning"
+)
+
+// Problem is something wrong with the structure of a project; Path is relative to the project
+type Problem struct {
+	Severity Severity `json:"severity"`
+	Path     string   `json:"path"`
+	Message  string   `json:"message"`
+}
+
+// checker walks a project the way Load does, but notes every problem instead of stopping at the
+// first one
+type checker struct {
+	root     string
+	problems []Problem
+	// the chapter the walk is in, and how many scenes it has so far
+	chapter       string
+	chapterScenes int
+	sawChapter    bool
+	// the first scene seen before any chapter, and the first chapter seen before any part
+	looseScene   string
+	sawPart      bool
+	looseChapter string
+}
+
+func (c *checker) report(severity Severity, path string, format string, a ...any) {
+	if rel, err := filepath.Rel(c.root, path); err == nil {
+		path = rel
+	}
+	c.problems = append(c.problems, Problem{Severity: severity, Path: path, Message: fmt.Sprintf(format, a...)})
+}
+
+// unknownKeys returns the keys in YAML data (as unmarshalled into value) that don't match a field
+// of the struct type t, recursing into nested structs and maps of structs
+func unknownKeys(prefix string, value any, t reflect.Type) (keys []string) {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	values, ok := value.(map[any]any)
+	if !ok {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		fields := map[string]reflect.Type{}
+		for i := 0; i < t.NumField(); i++ {
+			name, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+			fields[name] = t.Field(i).Type
+		}
+		for key, v := range values {
+			name := fmt.Sprint(key)
+			if field, known := fields[name]; known {
+				keys = append(keys, unknownKeys(prefix+name+".", v, field)...)
+			} else {
+				keys = append(keys, prefix+name)
+			}
+		}
+	case reflect.Map:
+		for key, v := range values {
+			keys = append(keys, unknownKeys(prefix+fmt.Sprint(key)+".", v, t.Elem())...)
+		}
+	}
+	sort.Strings(keys)
+	return
+}
+
+// checkYaml reports YAML that can't be read into meta, and keys that otis doesn't know about
+func (c *checker) checkYaml(path string, what string, data []byte, meta any) bool {
+	if err := yaml.Unmarshal(data, meta); err != nil {
+		c.report(SeverityError, path, "invalid %s: %s", what, err)
+		return false
+	}
+	var raw any
+	if err := yaml.Unmarshal(data, &raw); err == nil {
+		for _, key := range unknownKeys("", raw, reflect.TypeOf(meta)) {
+			c.report(SeverityWarning, path, "unknown key %s", key)
+		}
+	}
+	return true
+}
+
+func (c *checker) checkConfig() {
+	path := filepath.Join(c.root, "otis.yml")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		c.report(SeverityError, path, "%s", err)
+		return
+	}
+
+	if importPath := msfs.ImportPath(path, data); importPath != "" {
+		if _, err = os.Stat(importPath); err != nil {
+			c.report(SeverityError, path, "imported file %s does not exist", importPath)
+			return
+		}
+		if data, err = msfs.ReadFileWithImport(path); err != nil {
+			c.report(SeverityError, path, "%s", err)
+			return
+		}
+	}
+
+	var meta manuscriptMeta
+	c.checkYaml(path, "otis.yml", data, &meta)
+}
+
+// checkNumbers reports items in a directory without a number, numbers used more than once, gaps
+// in the numbering, and numbers of different widths that sort out of order
+func (c *checker) checkNumbers(dir string, names []string) {
+	used := map[int][]string{}
+	last, outOfOrder := -1, false
+	for _, name := range names {
+		num, err := msfs.FileNumber(name)
+		if err != nil {
+			c.report(SeverityWarning, filepath.Join(dir, name), "missing a number prefix")
+			continue
+		}
+		used[num] = append(used[num], name)
+		outOfOrder = outOfOrder || num < last
+		last = num
+	}
+
+	var numbers []int
+	for num := range used {
+		numbers = append(numbers, num)
+	}
+	sort.Ints(numbers)
+	for i, num := range numbers {
+		if len(used[num]) > 1 {
+			c.report(SeverityWarning, dir, "%s have the same number", strings.Join(used[num], " and "))
+		}
+		if i > 0 && num > numbers[i-1]+1 {
+			if num == numbers[i-1]+2 {
+				c.report(SeverityWarning, dir, "nothing is numbered %d", num-1)
+			} else {
+				c.report(SeverityWarning, dir, "nothing is numbered %d to %d", numbers[i-1]+1, num-1)
+			}
+		}
+	}
+	if outOfOrder {
+		c.report(SeverityWarning, dir, "numbers have different widths, so they don't sort in order")
+	}
+}
+
+// endChapter reports the chapter the walk was in if it has no scenes
+func (c *checker) endChapter() {
+	if c.chapter != "" && c.chapterScenes == 0 {
+		c.report(SeverityWarning, c.chapter, "chapter has no scenes")
+	}
+}
+
+func (c *checker) checkChapterMeta(path string) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		c.report(SeverityError, path, "%s", err)
+		return
+	}
+	var meta *chapterMeta
+	if c.checkYaml(path, "chapter.yml", data, &meta) {
+		if !c.sawPart && c.looseChapter == "" {
+			c.looseChapter = path
+		}
+		c.endChapter()
+		c.chapter, c.chapterScenes, c.sawChapter = path, 0, true
+	}
+}
+
+func (c *checker) checkPartMeta(path string) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		c.report(SeverityError, path, "%s", err)
+		return
+	}
+	var meta *partMeta
+	if c.checkYaml(path, "part.yml", data, &meta) {
+		c.sawPart = true
+	}
+}
+
+func (c *checker) checkScene(path string) {
+	if c.chapter != "" {
+		c.chapterScenes++
+	} else if c.looseScene == "" {
+		c.looseScene = path
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		c.report(SeverityError, path, "%s", err)
+		return
+	}
+	frontMatter, body := splitFrontMatter(content)
+	if frontMatter != nil {
+		var meta *sceneMeta
+		c.checkYaml(path, "front matter", frontMatter, &meta)
+	}
+	if strings.TrimSpace(string(body)) == "" {
+		c.report(SeverityWarning, path, "scene is empty")
+	}
+}
+
+// checkSections checks a front or back matter folder, if the project has one
+func (c *checker) checkSections(dir string) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return
+	} else if err != nil {
+		c.report(SeverityError, dir, "%s", err)
+		return
+	}
+
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		switch {
+		case strings.HasPrefix(entry.Name(), "."):
+			continue
+		case entry.IsDir() || filepath.Ext(entry.Name()) != ".md":
+			c.report(SeverityError, path, "unexpected file in %s (only sections belong here)", filepath.Base(dir))
+			continue
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			c.report(SeverityError, path, "%s", err)
+			continue
+		}
+		frontMatter, body := splitFrontMatter(content)
+		if frontMatter != nil {
+			var meta *sectionMeta
+			c.checkYaml(path, "front matter", frontMatter, &meta)
+		}
+		if strings.TrimSpace(string(body)) == "" {
+			c.report(SeverityWarning, path, "section is empty")
+		}
+	}
+}
+
+// checkDir checks a manuscript folder and everything in it, in the same order node.walk visits
+// them
+func (c *checker) checkDir(dir string) {
+	if _, err := os.Stat(filepath.Join(dir, "part.yml")); err == nil {
+		c.checkPartMeta(filepath.Join(dir, "part.yml"))
+	}
+	if _, err := os.Stat(filepath.Join(dir, "chapter.yml")); err == nil {
+		c.checkChapterMeta(filepath.Join(dir, "chapter.yml"))
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		c.report(SeverityError, dir, "%s", err)
+		return
+	}
+
+	var names []string
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		switch {
+		case strings.HasPrefix(entry.Name(), ".") || metaFilenames[entry.Name()]:
+			continue
+		case entry.IsDir():
+			names = append(names, entry.Name())
+			c.checkDir(path)
+		case filepath.Ext(entry.Name()) == ".md":
+			names = append(names, entry.Name())
+			c.checkScene(path)
+		default:
+			c.report(SeverityError, path, "unexpected file in manuscript (only scenes, folders, chapter.yml, and part.yml belong here)")
+		}
+	}
+
+	c.checkNumbers(dir, names)
+}
+
+// Check looks for problems in the structure of the project containing path; unlike Load, it
+// reports everything it finds rather than stopping at the first problem
+func Check(path string) (problems []Problem, err error) {
+	root, err := findProjectRoot(path)
+	if err != nil {
+		return
+	}
+
+	c := &checker{root: root}
+	c.checkConfig()
+	c.checkDir(filepath.Join(root, "manuscript"))
+	c.endChapter()
+	if c.sawChapter && c.looseScene != "" {
+		c.report(SeverityError, c.looseScene, "scene comes before the first chapter")
+	}
+	if c.sawPart && c.looseChapter != "" {
+		c.report(SeverityError, c.looseChapter, "chapter comes before the first part")
+	}
+	c.checkSections(filepath.Join(root, frontMatterDir))
+	c.checkSections(filepath.Join(root, backMatterDir))
+
+	return c.problems, nil
+}
@@ -0,0 +1,35 @@
+package ms
+
+import (
+	"gwcoffey/otis/fixture"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	problems, err := Check(fixture.Path("invalid/problems"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Problem{
+		{SeverityWarning, "otis.yml", "unknown key colour"},
+		{SeverityWarning, "manuscript/01-part/00-b.md", "scene is empty"},
+		{SeverityError, "manuscript/01-part/notes.txt", "unexpected file in manuscript (only scenes, folders, chapter.yml, and part.yml belong here)"},
+		{SeverityWarning, "manuscript/01-part/untitled.md", "missing a number prefix"},
+		{SeverityWarning, "manuscript/01-part", "00-a.md and 00-b.md have the same number"},
+		{SeverityWarning, "manuscript/01-part", "nothing is numbered 1 to 2"},
+		{SeverityError, "manuscript/02-empty/chapter.yml", "invalid chapter.yml: yaml: line 1: did not find expected node content"},
+		{SeverityError, "manuscript/00-loose.md", "scene comes before the first chapter"},
+		{SeverityWarning, "frontmatter/00-dedication.md", "unknown key author"},
+		{SeverityWarning, "backmatter/00-about.md", "section is empty"},
+		{SeverityError, "backmatter/photo.jpg", "unexpected file in backmatter (only sections belong here)"},
+	}
+	if len(problems) != len(expected) {
+		t.Fatalf("expected %d problems, got %d: %v", len(expected), len(problems), problems)
+	}
+	for i := range expected {
+		if problems[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected[i], problems[i])
+		}
+	}
+}
@@ -0,0 +1,296 @@
+package docx
+
+import (
+	"archive/zip"
+	"bytes"
+	_ "embed"
+	"encoding/xml"
+	"github.com/gomarkdown/markdown"
+	ms2 "gwcoffey/otis/ms"
+	"gwcoffey/otis/ms/compile"
+	"gwcoffey/otis/ms/compile/md"
+	"io"
+	"strings"
+)
+
+//go:embed content-types.xml
+var contentTypesXml []byte
+
+//go:embed rels.xml
+var relsXml []byte
+
+//go:embed document.xml.rels
+var documentRelsXml []byte
+
+//go:embed styles.xml
+var stylesXml []byte
+
+//go:embed settings.xml
+var settingsXml []byte
+
+const xmlHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n"
+const wordNamespace = `xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"`
+
+// escape makes text safe to insert into an XML document
+func escape(text string) string {
+	b := strings.Builder{}
+	if err := xml.EscapeText(&b, []byte(text)); err != nil {
+		panic(err)
+	}
+	return b.String()
+}
+
+// runStyle is the character formatting of a run
+type runStyle struct {
+	underline bool
+	bold      bool
+	strike    bool
+}
+
+// writeRun writes a single run of text, preserving leading and trailing spaces
+func writeRun(text string, style runStyle, out io.Writer) {
+	if text == "" {
+		return
+	}
+	_, _ = io.WriteString(out, "<w:r>")
+	if style != (runStyle{}) {
+		_, _ = io.WriteString(out, "<w:rPr>")
+		if style.bold {
+			_, _ = io.WriteString(out, "<w:b/>")
+		}
+		if style.strike {
+			_, _ = io.WriteString(out, "<w:strike/>")
+		}
+		if style.underline {
+			_, _ = io.WriteString(out, `<w:u w:val="single"/>`)
+		}
+		_, _ = io.WriteString(out, "</w:rPr>")
+	}
+	_, _ = io.WriteString(out, `<w:t xml:space="preserve">`)
+	_, _ = io.WriteString(out, escape(text))
+	_, _ = io.WriteString(out, "</w:t></w:r>")
+}
+
+// writeParagraph writes a single-run paragraph in the given style
+func writeParagraph(style string, line string, out *strings.Builder) {
+	out.WriteString(`<w:p><w:pPr><w:pStyle w:val="`)
+	out.WriteString(style)
+	out.WriteString(`"/></w:pPr>`)
+	writeRun(line, runStyle{}, out)
+	out.WriteString("</w:p>\n")
+}
+
+// writeScene writes a scene break (if needed) and then the scene itself
+func writeScene(scidx int, scene md.Source, pageBreak bool, opts compile.Options, out *strings.Builder) (err error) {
+	if scidx > 0 {
+		writeParagraph("SceneBreak", opts.SceneBreak, out)
+	}
+	doc, err := md.ParseScene(scene)
+	if err != nil {
+		return
+	}
+	out.Write(markdown.Render(doc, &renderer{sceneBreak: opts.SceneBreak, pageBreak: pageBreak}))
+	return
+}
+
+// writeSection writes a front or back matter section, headed like an unnumbered chapter; a section
+// without a title has no heading, and its text starts the new page instead
+func writeSection(section ms2.Section, atTop bool, opts compile.Options, out *strings.Builder) (err error) {
+	if section.Title() == "" {
+		return writeScene(0, section, !atTop, opts, out)
+	}
+	out.WriteString(`<w:p><w:pPr><w:pStyle w:val="Heading1"/>`)
+	if atTop {
+		out.WriteString(`<w:pageBreakBefore w:val="0"/>`)
+	}
+	out.WriteString("</w:pPr>")
+	writeRun(section.Title(), runStyle{}, out)
+	out.WriteString("</w:p>\n")
+	return writeScene(0, section, false, opts, out)
+}
+
+func writeTitlePage(m ms2.Manuscript, opts compile.Options, out *strings.Builder) (err error) {
+	wcount, err := compile.TitleWordCount(m, opts)
+	if err != nil {
+		return
+	}
+
+	// author name and word count on the first line, then the address block
+	out.WriteString(`<w:p><w:pPr><w:pStyle w:val="Contact"/></w:pPr>`)
+	writeRun(m.AuthorRealName(), runStyle{}, out)
+	out.WriteString("<w:r><w:tab/></w:r>")
+	writeRun(wcount+" words", runStyle{}, out)
+	out.WriteString("</w:p>\n")
+	for _, line := range strings.Split(m.AuthorAddress(), "\n") {
+		out.WriteString(`<w:p><w:pPr><w:pStyle w:val="Contact"/></w:pPr>`)
+		writeRun(line, runStyle{}, out)
+		out.WriteString("</w:p>\n")
+	}
+
+	// title and byline
+	out.WriteString(`<w:p><w:pPr><w:pStyle w:val="Title"/></w:pPr>`)
+	writeRun(m.Title(), runStyle{}, out)
+	out.WriteString("</w:p>\n")
+	out.WriteString(`<w:p><w:pPr><w:pStyle w:val="Byline"/></w:pPr>`)
+	writeRun("By "+m.AuthorName(), runStyle{}, out)
+	out.WriteString("</w:p>\n")
+	return
+}
+
+func documentXml(m ms2.Manuscript, opts compile.Options) (document string, err error) {
+	out := strings.Builder{}
+	out.WriteString(xmlHeader)
+	out.WriteString(`<w:document ` + wordNamespace + ` xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">`)
+	out.WriteString("<w:body>\n")
+
+	if opts.TitlePage {
+		err = writeTitlePage(m, opts, &out)
+		if err != nil {
+			return
+		}
+	}
+
+	// without a title page the first heading is already at the top of the document
+	frontMatter := compile.FrontMatter(m, opts)
+	for i, section := range frontMatter {
+		if err = writeSection(section, i == 0 && !opts.TitlePage, opts, &out); err != nil {
+			return
+		}
+	}
+	// the content starts a new page if anything comes before it
+	newPage := opts.TitlePage || len(frontMatter) > 0
+
+	// content
+	if len(m.Chapters()) > 0 {
+		for chidx, chapter := range m.Chapters() {
+			atTop := chidx == 0 && !newPage
+			if part := compile.PartStartingWith(m, chapter); part != nil {
+				out.WriteString(`<w:p><w:pPr><w:pStyle w:val="PartTitle"/>`)
+				if atTop {
+					out.WriteString(`<w:pageBreakBefore w:val="0"/>`)
+				}
+				out.WriteString("</w:pPr>")
+				label, title := compile.PartHeading(part)
+				writeRun(label, runStyle{}, &out)
+				if title != "" {
+					out.WriteString("<w:r><w:br/></w:r>")
+					writeRun(title, runStyle{}, &out)
+				}
+				out.WriteString("</w:p>\n")
+				atTop = false
+			}
+
+			out.WriteString(`<w:p><w:pPr><w:pStyle w:val="Heading1"/>`)
+			if atTop {
+				out.WriteString(`<w:pageBreakBefore w:val="0"/>`)
+			}
+			out.WriteString("</w:pPr>")
+			label, title := chapter.Heading(opts.ChapterHeading)
+			if label != "" {
+				writeRun(label, runStyle{}, &out)
+				out.WriteString("<w:r><w:br/></w:r>")
+			}
+			writeRun(title, runStyle{}, &out)
+			out.WriteString("</w:p>\n")
+
+			for scidx, scene := range chapter.Scenes() {
+				err = writeScene(scidx, scene, false, opts, &out)
+				if err != nil {
+					return
+				}
+			}
+		}
+	} else { // no chapters
+		for scidx, scene := range m.Scenes() {
+			err = writeScene(scidx, scene, scidx == 0 && newPage, opts, &out)
+			if err != nil {
+				return
+			}
+		}
+	}
+
+	// end marker
+	writeParagraph("SceneBreak", "# # # # #", &out)
+
+	// back matter follows the end of the story
+	for _, section := range compile.BackMatter(m, opts) {
+		if err = writeSection(section, false, opts, &out); err != nil {
+			return
+		}
+	}
+
+	// letter-sized page with 1 inch margins; the title page (if any) has no header
+	out.WriteString(`<w:sectPr><w:headerReference w:type="default" r:id="rId3"/>`)
+	out.WriteString(`<w:pgSz w:w="12240" w:h="15840"/>`)
+	out.WriteString(`<w:pgMar w:top="1440" w:right="1440" w:bottom="1440" w:left="1440" w:header="720" w:footer="720" w:gutter="0"/>`)
+	if opts.TitlePage {
+		out.WriteString(`<w:titlePg/>`)
+	}
+	out.WriteString(`</w:sectPr>`)
+
+	out.WriteString("</w:body></w:document>\n")
+
+	document = out.String()
+	return
+}
+
+func headerXml(m ms2.Manuscript) string {
+	out := strings.Builder{}
+	out.WriteString(xmlHeader)
+	out.WriteString(`<w:hdr ` + wordNamespace + `>`)
+	out.WriteString(`<w:p><w:pPr><w:pStyle w:val="Header"/></w:pPr>`)
+	writeRun(m.AuthorSurname()+" / "+strings.ToUpper(m.RunningTitle())+" / ", runStyle{}, &out)
+	out.WriteString(`<w:fldSimple w:instr=" PAGE "><w:r><w:t>1</w:t></w:r></w:fldSimple>`)
+	out.WriteString("</w:p></w:hdr>\n")
+	return out.String()
+}
+
+func coreXml(m ms2.Manuscript) string {
+	out := strings.Builder{}
+	out.WriteString(xmlHeader)
+	out.WriteString(`<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties" xmlns:dc="http://purl.org/dc/elements/1.1/">`)
+	out.WriteString("<dc:title>" + escape(m.Title()) + "</dc:title>")
+	out.WriteString("<dc:creator>" + escape(m.AuthorName()) + "</dc:creator>")
+	out.WriteString("</cp:coreProperties>\n")
+	return out.String()
+}
+
+// ManuscriptToDocx builds an Office Open XML document in standard manuscript format
+func ManuscriptToDocx(m ms2.Manuscript, opts compile.Options) (docx []byte, err error) {
+	document, err := documentXml(m, opts)
+	if err != nil {
+		return
+	}
+
+	parts := []struct {
+		name    string
+		content []byte
+	}{
+		{"[Content_Types].xml", contentTypesXml},
+		{"_rels/.rels", relsXml},
+		{"docProps/core.xml", []byte(coreXml(m))},
+		{"word/_rels/document.xml.rels", documentRelsXml},
+		{"word/document.xml", []byte(document)},
+		{"word/styles.xml", stylesXml},
+		{"word/settings.xml", settingsXml},
+		{"word/header1.xml", []byte(headerXml(m))},
+	}
+
+	out := bytes.Buffer{}
+	zw := zip.NewWriter(&out)
+	for _, part := range parts {
+		w, cerr := zw.Create(part.name)
+		if cerr != nil {
+			return nil, cerr
+		}
+		if _, err = w.Write(part.content); err != nil {
+			return
+		}
+	}
+	if err = zw.Close(); err != nil {
+		return
+	}
+
+	docx = out.Bytes()
+	return
+}
@@ -0,0 +1,63 @@
+package docx
+
+import (
+	"github.com/gomarkdown/markdown"
+	"gwcoffey/otis/fixture"
+	"gwcoffey/otis/ms"
+	"gwcoffey/otis/ms/compile"
+	"gwcoffey/otis/ms/compile/md"
+	"strings"
+	"testing"
+)
+
+const body = `<w:p><w:pPr><w:pStyle w:val="BodyText"/></w:pPr>`
+
+func TestRenderEmphasis(t *testing.T) {
+	expectRender(t, `clean`, body+`<w:r><w:t xml:space="preserve">clean</w:t></w:r></w:p>`)
+	expectRender(t, `this *is* neat`, body+
+		`<w:r><w:t xml:space="preserve">this </w:t></w:r>`+
+		`<w:r><w:rPr><w:u w:val="single"/></w:rPr><w:t xml:space="preserve">is</w:t></w:r>`+
+		`<w:r><w:t xml:space="preserve"> neat</w:t></w:r></w:p>`)
+	expectRender(t, `**all of it**`, body+`<w:r><w:rPr><w:b/></w:rPr><w:t xml:space="preserve">all of it</w:t></w:r></w:p>`)
+}
+
+func TestRenderEscapes(t *testing.T) {
+	expectRender(t, `A&B \*d\*`, body+
+		`<w:r><w:t xml:space="preserve">A&amp;B </w:t></w:r>`+
+		`<w:r><w:t xml:space="preserve">*</w:t></w:r>`+
+		`<w:r><w:t xml:space="preserve">d</w:t></w:r>`+
+		`<w:r><w:t xml:space="preserve">*</w:t></w:r></w:p>`)
+}
+
+func TestRenderQuote(t *testing.T) {
+	expectRender(t, `> quoted`, `<w:p><w:pPr><w:pStyle w:val="Quote"/><w:ind w:left="720" w:right="720" w:firstLine="0"/></w:pPr>`+
+		`<w:r><w:t xml:space="preserve">quoted</w:t></w:r></w:p>`)
+}
+
+func TestUntitledSection(t *testing.T) {
+	m, err := ms.Load(fixture.Path("parts"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	document, err := documentXml(m, compile.DefaultOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the dedication has no title, so its text starts the page after the title page with no heading
+	dedication := `<w:p><w:pPr><w:pStyle w:val="BodyText"/><w:pageBreakBefore/></w:pPr>` +
+		`<w:r><w:t xml:space="preserve">For the staff.</w:t></w:r></w:p>`
+	if !strings.Contains(document, dedication) {
+		t.Errorf("expected %q in\n%s", dedication, document)
+	}
+	if strings.Count(document, `<w:pStyle w:val="Heading1"/>`) != 3 {
+		t.Errorf("expected headings for the two chapters and the back matter only in\n%s", document)
+	}
+}
+
+func expectRender(t *testing.T, text string, expected string) {
+	actual := strings.TrimSpace(string(markdown.Render(md.Parse(text), &renderer{})))
+	if actual != expected {
+		t.Error("expected", actual, "to equal", expected)
+	}
+}
@@ -0,0 +1,99 @@
+package docx
+
+import (
+	"fmt"
+	"github.com/gomarkdown/markdown/ast"
+	"gwcoffey/otis/ms/compile/md"
+	"io"
+	"strings"
+)
+
+// renderer renders a scene's markdown AST as WordprocessingML paragraphs
+type renderer struct {
+	style runStyle
+	// sceneBreak is the text written for a scene break within the scene
+	sceneBreak string
+	// pageBreak is set when the first paragraph of the scene should start a new page
+	pageBreak bool
+}
+
+// paragraphStart opens a paragraph styled to suit its context: body text gets a first-line indent
+// (from the BodyText style), quotes are indented on both sides, and list items hang from their marker
+func (r *renderer) paragraphStart(style string, ctx md.BlockContext) string {
+	left := 720 * (ctx.QuoteDepth + ctx.ListDepth)
+	right := 720 * ctx.QuoteDepth
+	if style == "BodyText" && ctx.QuoteDepth > 0 && ctx.ListDepth == 0 {
+		style = "Quote"
+	}
+
+	builder := strings.Builder{}
+	builder.WriteString(`<w:p><w:pPr><w:pStyle w:val="` + style + `"/>`)
+	if r.pageBreak {
+		builder.WriteString("<w:pageBreakBefore/>")
+		r.pageBreak = false
+	}
+	if ctx.Marker != "" {
+		builder.WriteString(fmt.Sprintf(`<w:tabs><w:tab w:val="left" w:pos="%d"/></w:tabs>`, left))
+		builder.WriteString(fmt.Sprintf(`<w:ind w:left="%d" w:right="%d" w:hanging="360"/>`, left, right))
+	} else if left > 0 {
+		builder.WriteString(fmt.Sprintf(`<w:ind w:left="%d" w:right="%d" w:firstLine="0"/>`, left, right))
+	}
+	builder.WriteString("</w:pPr>")
+	if ctx.Marker != "" {
+		writeRun(ctx.Marker, runStyle{}, &builder)
+		builder.WriteString("<w:r><w:tab/></w:r>")
+	}
+	return builder.String()
+}
+
+func (r *renderer) RenderNode(w io.Writer, node ast.Node, entering bool) ast.WalkStatus {
+	write := func(s string) {
+		_, _ = io.WriteString(w, s)
+	}
+
+	if md.IsSceneBreak(node) {
+		if entering {
+			write(r.paragraphStart("SceneBreak", md.BlockContext{}))
+			writeRun(r.sceneBreak, runStyle{}, w)
+			write("</w:p>\n")
+		}
+		return ast.SkipChildren
+	}
+
+	switch n := node.(type) {
+	case *ast.Text, *ast.Code, *ast.HTMLSpan:
+		writeRun(strings.ReplaceAll(string(n.AsLeaf().Literal), "\n", " "), r.style, w)
+	case *ast.Emph:
+		r.style.underline = entering
+	case *ast.Strong:
+		r.style.bold = entering
+	case *ast.Del:
+		r.style.strike = entering
+	case *ast.Hardbreak:
+		write("<w:r><w:br/></w:r>")
+	case *ast.Paragraph:
+		if entering {
+			write(r.paragraphStart("BodyText", md.Context(n)))
+		} else {
+			write("</w:p>\n")
+		}
+	case *ast.Heading:
+		if entering {
+			write(r.paragraphStart("SceneBreak", md.BlockContext{}))
+			writeRun(md.PlainText(n), runStyle{}, w)
+			write("</w:p>\n")
+		}
+		return ast.SkipChildren
+	case *ast.CodeBlock:
+		for _, line := range strings.Split(strings.TrimSuffix(string(n.Literal), "\n"), "\n") {
+			write(r.paragraphStart("Normal", md.Context(n)))
+			writeRun(line, runStyle{}, w)
+			write("</w:p>\n")
+		}
+	}
+	return ast.GoToNext
+}
+
+func (r *renderer) RenderHeader(_ io.Writer, _ ast.Node) {}
+
+func (r *renderer) RenderFooter(_ io.Writer, _ ast.Node) {}
@@ -0,0 +1,256 @@
+package epub
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha1"
+	_ "embed"
+	"encoding/xml"
+	"fmt"
+	mdhtml "github.com/gomarkdown/markdown/html"
+	ms2 "gwcoffey/otis/ms"
+	"gwcoffey/otis/ms/compile"
+	"gwcoffey/otis/ms/compile/html"
+	"gwcoffey/otis/ms/compile/md"
+	htemplate "html/template"
+	"io"
+	"strings"
+	ttemplate "text/template"
+	"time"
+)
+
+// chapterData describes one content document in the package; a manuscript with no chapters
+// is packaged as a single untitled content document, each part gets a document of its own
+// with no scenes, and each front or back matter section gets a document with just its text
+type chapterData struct {
+	Id     string
+	Label  string
+	Title  string
+	Part   bool
+	Matter bool
+	Scenes []md.Source
+}
+
+type templateData struct {
+	Manuscript ms2.Manuscript
+	WordCount  string
+	Identifier string
+	Modified   string
+	Chapters   []chapterData
+	Options    compile.Options
+}
+
+type chapterTemplateData struct {
+	Manuscript ms2.Manuscript
+	Chapter    chapterData
+	Options    compile.Options
+}
+
+//go:embed container.xml
+var containerXml []byte
+
+//go:embed style.css
+var styleCss []byte
+
+//go:embed content.opf.tmpl
+var opfTemplateText string
+
+//go:embed nav.xhtml.tmpl
+var navTemplateText string
+
+//go:embed title.xhtml.tmpl
+var titleTemplateText string
+
+//go:embed chapter.xhtml.tmpl
+var chapterTemplateText string
+
+// sceneBreak returns the XHTML for a scene break; e-readers don't reliably support generated
+// content, so the break text is written out rather than added to an <hr> with css
+func sceneBreak(opts compile.Options) string {
+	return `<p class="scene-break">` + htemplate.HTMLEscapeString(opts.SceneBreak) + "</p>\n"
+}
+
+func loadXhtmlTemplate(name string, text string, opts compile.Options) (*htemplate.Template, error) {
+	return htemplate.New(name).
+		Funcs(htemplate.FuncMap{
+			// smartypants is deliberately left off because it produces named entities (like
+			// &rsquo;) that are not valid in EPUB content documents, and raw HTML is escaped
+			// because it usually isn't valid XHTML
+			"markdown": func(s string) htemplate.HTML {
+				return html.RenderMarkdown(s, mdhtml.UseXHTML, sceneBreak(opts), true)
+			},
+			"sceneBreak": func() htemplate.HTML {
+				return htemplate.HTML(sceneBreak(opts))
+			},
+		}).
+		Parse(text)
+}
+
+func loadOpfTemplate() (*ttemplate.Template, error) {
+	return ttemplate.New("opf").
+		Funcs(ttemplate.FuncMap{
+			"xml": func(s string) (string, error) {
+				b := strings.Builder{}
+				err := xml.EscapeText(&b, []byte(s))
+				return b.String(), err
+			},
+		}).
+		Parse(opfTemplateText)
+}
+
+// identifier derives a stable unique identifier for the book from its title and author so
+// rebuilding the same manuscript produces the same package identity
+func identifier(m ms2.Manuscript) string {
+	sum := sha1.Sum([]byte(m.Title() + "\x00" + m.AuthorName()))
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
+
+// sources converts scenes to the markdown sources of a content document
+func sources(scenes []ms2.Scene) (result []md.Source) {
+	for _, scene := range scenes {
+		result = append(result, scene)
+	}
+	return
+}
+
+// matter returns a content document for each front or back matter section
+func matter(prefix string, sections []ms2.Section) (result []chapterData) {
+	for i, section := range sections {
+		result = append(result, chapterData{
+			Id:     fmt.Sprintf("%s-%02d", prefix, i+1),
+			Title:  section.Title(),
+			Matter: true,
+			Scenes: []md.Source{section},
+		})
+	}
+	return
+}
+
+func chapters(m ms2.Manuscript, opts compile.Options) (result []chapterData) {
+	result = matter("front", compile.FrontMatter(m, opts))
+	if len(m.Chapters()) == 0 {
+		result = append(result, chapterData{Id: "content", Scenes: sources(m.Scenes())})
+	}
+
+	for i, chapter := range m.Chapters() {
+		if part := compile.PartStartingWith(m, chapter); part != nil {
+			label, title := compile.PartHeading(part)
+			result = append(result, chapterData{
+				Id:    fmt.Sprintf("part-%02d", part.Number()),
+				Label: label,
+				Title: title,
+				Part:  true,
+			})
+		}
+		label, title := chapter.Heading(opts.ChapterHeading)
+		result = append(result, chapterData{
+			Id:     fmt.Sprintf("chapter-%02d", i+1),
+			Label:  label,
+			Title:  title,
+			Scenes: sources(chapter.Scenes()),
+		})
+	}
+	return append(result, matter("back", compile.BackMatter(m, opts))...)
+}
+
+// writeEntry adds a compressed file to the archive
+func writeEntry(zw *zip.Writer, name string, content []byte) (err error) {
+	w, err := zw.Create(name)
+	if err != nil {
+		return
+	}
+	_, err = w.Write(content)
+	return
+}
+
+type executable interface {
+	Execute(w io.Writer, data any) error
+}
+
+// writeTemplateEntry executes a template and adds the result to the archive
+func writeTemplateEntry(zw *zip.Writer, name string, tmpl executable, data any) (err error) {
+	out := bytes.Buffer{}
+	err = tmpl.Execute(&out, data)
+	if err != nil {
+		return
+	}
+	return writeEntry(zw, name, out.Bytes())
+}
+
+// ManuscriptToEpub builds an EPUB 3 package containing a title page (unless it is turned off), one
+// content document per chapter, and one per front or back matter section
+func ManuscriptToEpub(m ms2.Manuscript, opts compile.Options) (epub []byte, err error) {
+	wordcount, err := compile.TitleWordCount(m, opts)
+	if err != nil {
+		return
+	}
+
+	data := templateData{
+		Manuscript: m,
+		WordCount:  wordcount,
+		Identifier: identifier(m),
+		Modified:   time.Now().UTC().Format("2006-01-02T15:04:05Z"),
+		Chapters:   chapters(m, opts),
+		Options:    opts,
+	}
+
+	opfTemplate, err := loadOpfTemplate()
+	if err != nil {
+		return
+	}
+	navTemplate, err := loadXhtmlTemplate("nav", navTemplateText, opts)
+	if err != nil {
+		return
+	}
+	titleTemplate, err := loadXhtmlTemplate("title", titleTemplateText, opts)
+	if err != nil {
+		return
+	}
+	chapterTemplate, err := loadXhtmlTemplate("chapter", chapterTemplateText, opts)
+	if err != nil {
+		return
+	}
+
+	out := bytes.Buffer{}
+	zw := zip.NewWriter(&out)
+
+	// the mimetype must be the first entry and must be stored uncompressed
+	w, err := zw.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		return
+	}
+	if _, err = w.Write([]byte("application/epub+zip")); err != nil {
+		return
+	}
+
+	if err = writeEntry(zw, "META-INF/container.xml", containerXml); err != nil {
+		return
+	}
+	if err = writeEntry(zw, "OEBPS/style.css", styleCss); err != nil {
+		return
+	}
+	if err = writeTemplateEntry(zw, "OEBPS/content.opf", opfTemplate, data); err != nil {
+		return
+	}
+	if err = writeTemplateEntry(zw, "OEBPS/nav.xhtml", navTemplate, data); err != nil {
+		return
+	}
+	if opts.TitlePage {
+		if err = writeTemplateEntry(zw, "OEBPS/title.xhtml", titleTemplate, data); err != nil {
+			return
+		}
+	}
+	for _, chapter := range data.Chapters {
+		err = writeTemplateEntry(zw, "OEBPS/"+chapter.Id+".xhtml", chapterTemplate, chapterTemplateData{Manuscript: m, Chapter: chapter, Options: opts})
+		if err != nil {
+			return
+		}
+	}
+
+	if err = zw.Close(); err != nil {
+		return
+	}
+
+	epub = out.Bytes()
+	return
+}
@@ -0,0 +1,115 @@
+package epub
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"gwcoffey/otis/fixture"
+	"gwcoffey/otis/ms"
+	"gwcoffey/otis/ms/compile"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func compileParts(t *testing.T) *zip.Reader {
+	m, err := ms.Load(fixture.Path("parts"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	epub, err := ManuscriptToEpub(m, compile.DefaultOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(epub), int64(len(epub)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return zr
+}
+
+func read(t *testing.T, f *zip.File) string {
+	r, err := f.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	content, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content)
+}
+
+func TestContainerLayout(t *testing.T) {
+	zr := compileParts(t)
+
+	var names []string
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	expected := []string{
+		"mimetype",
+		"META-INF/container.xml",
+		"OEBPS/style.css",
+		"OEBPS/content.opf",
+		"OEBPS/nav.xhtml",
+		"OEBPS/title.xhtml",
+		"OEBPS/front-01.xhtml",
+		"OEBPS/part-01.xhtml",
+		"OEBPS/chapter-01.xhtml",
+		"OEBPS/chapter-02.xhtml",
+		"OEBPS/back-01.xhtml",
+	}
+	if !slices.Equal(names, expected) {
+		t.Fatalf("expected %v, got %v", expected, names)
+	}
+
+	// readers find the package by its first entry, which has to be stored as is
+	mimetype := zr.File[0]
+	if mimetype.Method != zip.Store || read(t, mimetype) != "application/epub+zip" {
+		t.Errorf("expected an uncompressed mimetype, got method %d and %q", mimetype.Method, read(t, mimetype))
+	}
+	if container := read(t, zr.File[1]); !strings.Contains(container, `full-path="OEBPS/content.opf"`) {
+		t.Errorf("expected the container to point at the package document, got\n%s", container)
+	}
+
+	opf := read(t, zr.File[3])
+	for _, name := range expected[4:] {
+		href := strings.TrimPrefix(name, "OEBPS/")
+		if !strings.Contains(opf, `href="`+href+`"`) {
+			t.Errorf("expected %s in the manifest\n%s", href, opf)
+		}
+	}
+}
+
+func TestContentIsXml(t *testing.T) {
+	for _, f := range compileParts(t).File {
+		if !strings.HasSuffix(f.Name, ".xhtml") && !strings.HasSuffix(f.Name, ".xml") && !strings.HasSuffix(f.Name, ".opf") {
+			continue
+		}
+		decoder := xml.NewDecoder(strings.NewReader(read(t, f)))
+		for {
+			if _, err := decoder.Token(); err == io.EOF {
+				break
+			} else if err != nil {
+				t.Errorf("expected %s to be well-formed: %v", f.Name, err)
+				break
+			}
+		}
+	}
+}
+
+func TestRawHtmlIsEscaped(t *testing.T) {
+	for _, f := range compileParts(t).File {
+		if f.Name != "OEBPS/chapter-01.xhtml" {
+			continue
+		}
+		if content := read(t, f); !strings.Contains(content, "She read the &lt;letter&gt; twice.") {
+			t.Errorf("expected the raw HTML as text in\n%s", content)
+		}
+		return
+	}
+	t.Error("expected a content document for the first chapter")
+}
@@ -3,53 +3,101 @@ package html
 import (
 	_ "embed"
 	"github.com/gomarkdown/markdown"
+	"github.com/gomarkdown/markdown/ast"
 	"github.com/gomarkdown/markdown/html"
-	"github.com/gomarkdown/markdown/parser"
 	ms2 "gwcoffey/otis/ms"
+	"gwcoffey/otis/ms/compile"
+	"gwcoffey/otis/ms/compile/md"
 	"html/template"
+	"io"
 	"strings"
 )
 
 type templateData struct {
 	Manuscript ms2.Manuscript
 	WordCount  string
+	Options    compile.Options
+	// FrontMatter and BackMatter are the sections to compile before and after the chapters
+	FrontMatter []ms2.Section
+	BackMatter  []ms2.Section
 }
 
 //go:embed output.html.tmpl
 var templateText string
 
-func loadTemplate() (tmpl *template.Template, err error) {
+func loadTemplate(m ms2.Manuscript) (tmpl *template.Template, err error) {
 	tmpl, err = template.New("document").
 		Funcs(template.FuncMap{
 			"breaks": func(s string) template.HTML {
 				return template.HTML(strings.Replace(template.HTMLEscapeString(s), "\n", "<br>", -1))
 			},
 			"markdown": func(s string) template.HTML {
-				extensions := parser.CommonExtensions
-				p := parser.NewWithExtensions(extensions)
-				doc := p.Parse([]byte(s))
-
-				htmlFlags := html.CommonFlags
-				opts := html.RendererOptions{Flags: htmlFlags}
-				renderer := html.NewRenderer(opts)
-
-				return template.HTML(markdown.Render(doc, renderer))
+				return RenderMarkdown(s, html.CommonFlags, "<hr>\n", false)
+			},
+			"heading": func(c ms2.Chapter, style compile.HeadingStyle) []string {
+				label, title := c.Heading(style)
+				return []string{label, title}
+			},
+			"part": func(c ms2.Chapter) []string {
+				if part := compile.PartStartingWith(m, c); part != nil {
+					label, title := compile.PartHeading(part)
+					return []string{label, title}
+				}
+				return nil
 			},
 		}).
 		Parse(templateText)
 	return
 }
 
-func ManuscriptToHtml(m ms2.Manuscript) (html string, err error) {
-	htemplate, err := loadTemplate()
+// RenderMarkdown renders scene markdown as HTML with the given renderer flags, writing sceneBreak
+// for scene breaks within the scene so they match the breaks between scenes. With escapeHTML, raw
+// HTML in the scene is written as text (the way the other formats show it) rather than copied into
+// the output, where it may not be valid (in XHTML, for instance).
+func RenderMarkdown(s string, flags html.Flags, sceneBreak string, escapeHTML bool) template.HTML {
+	opts := html.RendererOptions{
+		Flags: flags,
+		RenderNodeHook: func(w io.Writer, node ast.Node, entering bool) (ast.WalkStatus, bool) {
+			if md.IsSceneBreak(node) {
+				if entering {
+					_, _ = io.WriteString(w, sceneBreak)
+				}
+				return ast.SkipChildren, true
+			}
+			if escapeHTML {
+				switch n := node.(type) {
+				case *ast.HTMLSpan:
+					_, _ = io.WriteString(w, template.HTMLEscapeString(string(n.Literal)))
+					return ast.GoToNext, true
+				case *ast.HTMLBlock:
+					_, _ = io.WriteString(w, "<p>"+template.HTMLEscapeString(strings.TrimSpace(string(n.Literal)))+"</p>\n")
+					return ast.GoToNext, true
+				}
+			}
+			return ast.GoToNext, false
+		},
+	}
+	renderer := html.NewRenderer(opts)
+
+	return template.HTML(markdown.Render(md.Parse(s), renderer))
+}
+
+func ManuscriptToHtml(m ms2.Manuscript, opts compile.Options) (html string, err error) {
+	htemplate, err := loadTemplate(m)
 	out := strings.Builder{}
 
-	wordcount, err := ms2.ApproximateWordCount(m)
+	wordcount, err := compile.TitleWordCount(m, opts)
 	if err != nil {
 		return
 	}
 
-	err = htemplate.Execute(&out, templateData{Manuscript: m, WordCount: wordcount})
+	err = htemplate.Execute(&out, templateData{
+		Manuscript:  m,
+		WordCount:   wordcount,
+		Options:     opts,
+		FrontMatter: compile.FrontMatter(m, opts),
+		BackMatter:  compile.BackMatter(m, opts),
+	})
 	if err != nil {
 		return
 	}
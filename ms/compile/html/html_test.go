@@ -0,0 +1,47 @@
+package html
+
+import (
+	"gwcoffey/otis/fixture"
+	"gwcoffey/otis/ms"
+	"gwcoffey/otis/ms/compile"
+	"strings"
+	"testing"
+)
+
+func compileParts(t *testing.T, style compile.HeadingStyle) string {
+	m, err := ms.Load(fixture.Path("parts"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := compile.DefaultOptions()
+	opts.ChapterHeading = style
+	html, err := ManuscriptToHtml(m, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return html
+}
+
+func TestSubtitleAndEpigraph(t *testing.T) {
+	html := compileParts(t, compile.HeadingBoth)
+	for _, expected := range []string{
+		`<h2><span class="label">Chapter 1</span>100% Effort</h2>` + "\n" +
+			`                <p class="subtitle">In Which Nothing Goes to Plan</p>`,
+		`<p>Nothing ventured,<br>nothing gained.</p>` + "\n" +
+			`                    <p class="attribution">— Proverb</p>`,
+	} {
+		if !strings.Contains(html, expected) {
+			t.Errorf("expected %q in\n%s", expected, html)
+		}
+	}
+	if strings.Count(html, `<div class="epigraph">`) != 1 {
+		t.Errorf("expected an epigraph for the first chapter only in\n%s", html)
+	}
+}
+
+func TestSubtitleNeedsTitle(t *testing.T) {
+	// a subtitle goes with the title, so it isn't shown when the heading has only a number
+	if html := compileParts(t, compile.HeadingNumber); strings.Contains(html, "In Which Nothing Goes to Plan") {
+		t.Errorf("expected no subtitle in\n%s", html)
+	}
+}
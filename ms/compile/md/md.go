@@ -0,0 +1,112 @@
+package md
+
+import (
+	"fmt"
+	"github.com/gomarkdown/markdown/ast"
+	"github.com/gomarkdown/markdown/parser"
+	"strings"
+)
+
+// Extensions is the set of markdown extensions otis understands in scenes. Every compile target
+// parses with this one set so a scene means the same thing in every output format. (Notably, it
+// leaves out MathJax and tables, which would turn ordinary prose like "$3 and $5" into markup.)
+const Extensions = parser.NoIntraEmphasis | parser.FencedCode | parser.Strikethrough |
+	parser.SpaceHeadings | parser.BackslashLineBreak | parser.OrderedListStart
+
+// Parse parses markdown text into an AST
+func Parse(text string) ast.Node {
+	return parser.NewWithExtensions(Extensions).Parse([]byte(text))
+}
+
+// Source is something with markdown text to compile: a scene, or a front or back matter section
+type Source interface {
+	Text() (string, error)
+}
+
+// ParseScene reads and parses the text of a scene (or section)
+func ParseScene(scene Source) (doc ast.Node, err error) {
+	text, err := scene.Text()
+	if err != nil {
+		return
+	}
+	doc = Parse(text)
+	return
+}
+
+// BlockContext describes where a block sits in the document, which is what determines how it is
+// indented in the paragraph-oriented formats
+type BlockContext struct {
+	// QuoteDepth is the number of block quotes containing the block
+	QuoteDepth int
+	// ListDepth is the number of lists containing the block
+	ListDepth int
+	// Marker is the list marker to put before the block (like "•" or "3.") if the block is the
+	// first one in a list item, and empty otherwise
+	Marker string
+}
+
+// Context returns the context of a block node
+func Context(node ast.Node) (ctx BlockContext) {
+	if item, ok := node.GetParent().(*ast.ListItem); ok && ast.GetFirstChild(item) == node {
+		ctx.Marker = ListMarker(item)
+	}
+	for parent := node.GetParent(); parent != nil; parent = parent.GetParent() {
+		switch parent.(type) {
+		case *ast.BlockQuote:
+			ctx.QuoteDepth++
+		case *ast.List:
+			ctx.ListDepth++
+		}
+	}
+	return
+}
+
+// ListMarker returns the marker for a list item: its number for ordered lists, or a bullet
+func ListMarker(item *ast.ListItem) string {
+	if item.ListFlags&ast.ListTypeOrdered == 0 {
+		return "•"
+	}
+
+	number := 1
+	if list, ok := item.GetParent().(*ast.List); ok {
+		if list.Start > 0 {
+			number = list.Start
+		}
+		for _, sibling := range list.GetChildren() {
+			if sibling == item {
+				break
+			}
+			number++
+		}
+	}
+
+	delimiter := item.Delimiter
+	if delimiter == 0 {
+		delimiter = '.'
+	}
+	return fmt.Sprintf("%d%c", number, delimiter)
+}
+
+// IsSceneBreak reports whether a block is a scene break within a scene: either a horizontal rule
+// or a paragraph with nothing but a single "#" (the scene break marker in standard manuscript format)
+func IsSceneBreak(node ast.Node) bool {
+	switch n := node.(type) {
+	case *ast.HorizontalRule:
+		return true
+	case *ast.Paragraph:
+		return strings.TrimSpace(PlainText(n)) == "#"
+	}
+	return false
+}
+
+// PlainText returns the text content of a node and all its children, with no formatting
+func PlainText(node ast.Node) string {
+	var text []byte
+	ast.WalkFunc(node, func(n ast.Node, entering bool) ast.WalkStatus {
+		if leaf := n.AsLeaf(); leaf != nil && entering {
+			text = append(text, leaf.Literal...)
+		}
+		return ast.GoToNext
+	})
+	return string(text)
+}
@@ -0,0 +1,54 @@
+package md
+
+import (
+	"github.com/gomarkdown/markdown/ast"
+	"testing"
+)
+
+func TestParseLeavesDollarsAlone(t *testing.T) {
+	doc := Parse("it cost $3 and $5")
+	if expected, actual := "it cost $3 and $5", PlainText(doc); expected != actual {
+		t.Errorf("expected %v but got %v", expected, actual)
+	}
+}
+
+func TestEscapedAsterisks(t *testing.T) {
+	doc := Parse(`a \*literal\* b`)
+	if expected, actual := "a *literal* b", PlainText(doc); expected != actual {
+		t.Errorf("expected %v but got %v", expected, actual)
+	}
+}
+
+func TestContext(t *testing.T) {
+	doc := Parse("> 3. one\n> 4. two\n>    - nested\n")
+	var contexts []BlockContext
+	ast.WalkFunc(doc, func(node ast.Node, entering bool) ast.WalkStatus {
+		if _, ok := node.(*ast.Paragraph); ok && entering {
+			contexts = append(contexts, Context(node))
+		}
+		return ast.GoToNext
+	})
+
+	expected := []BlockContext{
+		{QuoteDepth: 1, ListDepth: 1, Marker: "3."},
+		{QuoteDepth: 1, ListDepth: 1, Marker: "4."},
+		{QuoteDepth: 1, ListDepth: 2, Marker: "•"},
+	}
+	if len(contexts) != len(expected) {
+		t.Fatalf("expected %d paragraphs but got %d", len(expected), len(contexts))
+	}
+	for i := range expected {
+		if contexts[i] != expected[i] {
+			t.Errorf("expected %+v but got %+v", expected[i], contexts[i])
+		}
+	}
+}
+
+func TestIsSceneBreak(t *testing.T) {
+	for text, expected := range map[string]bool{"#": true, "***": true, "# #": false, "a # b": false} {
+		block := ast.GetFirstChild(Parse(text))
+		if actual := IsSceneBreak(block); actual != expected {
+			t.Errorf("expected IsSceneBreak(%q) to be %v", text, expected)
+		}
+	}
+}
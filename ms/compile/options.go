@@ -0,0 +1,116 @@
+package compile
+
+import (
+	ms2 "gwcoffey/otis/ms"
+	"gwcoffey/otis/oerr"
+	"gwcoffey/otis/text"
+	"strings"
+)
+
+// HeadingStyle controls what appears at the top of each chapter (see ms.HeadingStyle)
+type HeadingStyle = ms2.HeadingStyle
+
+const (
+	HeadingBoth   = ms2.HeadingBoth
+	HeadingNumber = ms2.HeadingNumber
+	HeadingTitle  = ms2.HeadingTitle
+)
+
+// WordCountScope controls which words are counted for the title page
+type WordCountScope string
+
+const (
+	// CountWhole counts the whole manuscript, even when only an excerpt is compiled
+	CountWhole WordCountScope = "whole"
+	// CountExcerpt counts just the scenes being compiled
+	CountExcerpt WordCountScope = "excerpt"
+)
+
+// Options controls the parts of a compiled manuscript that can vary from one compile to the next;
+// every compile target honors them
+type Options struct {
+	// SceneBreak is the text centered on its own line between scenes
+	SceneBreak string
+	// ChapterHeading is the style of chapter headings
+	ChapterHeading HeadingStyle
+	// TitlePage includes the title page
+	TitlePage bool
+	// Sections includes the front and back matter sections (the dedication, acknowledgements, and
+	// so on) before and after the chapters
+	Sections bool
+	// WordCount is the scope of the word count on the title page
+	WordCount WordCountScope
+}
+
+// DefaultOptions returns the options for a standard manuscript
+func DefaultOptions() Options {
+	return Options{
+		SceneBreak:     "#",
+		ChapterHeading: HeadingBoth,
+		TitlePage:      true,
+		Sections:       true,
+		WordCount:      CountWhole,
+	}
+}
+
+// ParseHeadingStyle converts the name of a heading style to a HeadingStyle
+func ParseHeadingStyle(name string) (HeadingStyle, error) {
+	style := HeadingStyle(strings.ToLower(name))
+	switch style {
+	case HeadingBoth, HeadingNumber, HeadingTitle:
+		return style, nil
+	}
+	return "", oerr.InvalidCompileOption("chapterHeading", name)
+}
+
+// ParseWordCountScope converts the name of a word count scope to a WordCountScope
+func ParseWordCountScope(name string) (WordCountScope, error) {
+	scope := WordCountScope(strings.ToLower(name))
+	switch scope {
+	case CountWhole, CountExcerpt:
+		return scope, nil
+	}
+	return "", oerr.InvalidCompileOption("wordCount", name)
+}
+
+// TitleWordCount returns the approximate word count for the title page of a (possibly filtered)
+// manuscript; the whole manuscript still leaves out excluded scenes since they are never compiled
+func TitleWordCount(m ms2.Manuscript, opts Options) (string, error) {
+	if opts.WordCount == CountWhole {
+		m = m.Whole().Filter(ms2.NotExcluded)
+	}
+	return ms2.ApproximateWordCount(m)
+}
+
+// FrontMatter returns the front matter sections to compile, if the options include them
+func FrontMatter(m ms2.Manuscript, opts Options) []ms2.Section {
+	if !opts.Sections {
+		return nil
+	}
+	return m.FrontMatter()
+}
+
+// BackMatter returns the back matter sections to compile, if the options include them
+func BackMatter(m ms2.Manuscript, opts Options) []ms2.Section {
+	if !opts.Sections {
+		return nil
+	}
+	return m.BackMatter()
+}
+
+// PartStartingWith returns the part that begins with the given chapter, if there is one, so
+// compilers can write the part's title page before the chapter
+func PartStartingWith(m ms2.Manuscript, c ms2.Chapter) ms2.Part {
+	for _, part := range m.Parts() {
+		if chapters := part.Chapters(); len(chapters) > 0 && chapters[0].Path() == c.Path() {
+			return part
+		}
+	}
+	return nil
+}
+
+// PartHeading returns the two lines of a part's title page: the label (like "PART ONE", in capitals
+// as manuscripts set it, so every format shows it the same way) and the title, which may be empty
+func PartHeading(p ms2.Part) (label string, title string) {
+	return strings.ToUpper("Part " + text.NumberToWords(p.Number())), p.Title()
+}
@@ -0,0 +1,144 @@
+package pdf
+
+import (
+	"fmt"
+	"gwcoffey/otis/ms/compile/md"
+	"strings"
+)
+
+// page geometry, in points; everything is set in 12pt Courier, whose glyphs are all 600/1000 em
+// wide, so a line's width is just its length times charWidth
+const (
+	pageWidth   = 612.0
+	pageHeight  = 792.0
+	margin      = 72.0
+	fontSize    = 12.0
+	charWidth   = 7.2
+	lineHeight  = 24.0
+	indentWidth = 36.0
+	textWidth   = pageWidth - 2*margin
+	lineChars   = int(textWidth / charWidth)
+	indentChars = int(indentWidth / charWidth)
+	headerY     = pageHeight - 36.0 - fontSize
+	topY        = pageHeight - margin - fontSize
+)
+
+type align int
+
+const (
+	alignLeft align = iota
+	alignCenter
+	alignRight
+)
+
+// layout flows lines of text onto pages, starting new pages (with running headers) as needed
+type layout struct {
+	pages  []*strings.Builder
+	y      float64
+	header string
+	// number is the page number of the last page with a header; the title page isn't numbered, so
+	// numbering starts at 1 on the page after it
+	number int
+}
+
+// newPage starts a new page, with the running header and page number unless this is the title page
+func (l *layout) newPage(withHeader bool) {
+	l.pages = append(l.pages, &strings.Builder{})
+	if withHeader {
+		l.number++
+		l.text(plain(fmt.Sprintf("%s%d", l.header, l.number)), 0, headerY, alignRight)
+	}
+	l.y = topY
+}
+
+func (l *layout) current() *strings.Builder {
+	return l.pages[len(l.pages)-1]
+}
+
+// text places a single line of text with its baseline at y, offset from the left margin by indent
+// (or aligned within the text area)
+func (l *layout) text(chars []char, indent float64, y float64, a align) {
+	x := margin + indent
+	width := float64(len(chars)) * charWidth
+	switch a {
+	case alignCenter:
+		x = margin + (textWidth-width)/2
+	case alignRight:
+		x = margin + textWidth - width
+	}
+
+	out := l.current()
+	for i := 0; i < len(chars); {
+		// each run of characters in the same weight is set as one string
+		start := i
+		for i < len(chars) && chars[i].bold == chars[start].bold {
+			i++
+		}
+		font := "F1"
+		if chars[start].bold {
+			font = "F2"
+		}
+		out.WriteString(fmt.Sprintf("BT /%s %.0f Tf %.2f %.2f Td (%s) Tj ET\n", font, fontSize, x+float64(start)*charWidth, y, escapeString(chars[start:i])))
+	}
+
+	l.rule(chars, x, y-2, func(c char) bool { return c.underline })
+	l.rule(chars, x, y+3, func(c char) bool { return c.strike })
+}
+
+// rule draws a horizontal line at y under each run of characters matching the predicate
+func (l *layout) rule(chars []char, x float64, y float64, predicate func(char) bool) {
+	out := l.current()
+	for i := 0; i < len(chars); i++ {
+		if !predicate(chars[i]) {
+			continue
+		}
+		start := i
+		for i < len(chars) && predicate(chars[i]) {
+			i++
+		}
+		x1 := x + float64(start)*charWidth
+		x2 := x + float64(i)*charWidth
+		out.WriteString(fmt.Sprintf("%.2f %.2f m %.2f %.2f l S\n", x1, y, x2, y))
+	}
+}
+
+// line places one line at the cursor and advances by the given leading, breaking to a new page
+// first if the line won't fit
+func (l *layout) line(chars []char, indent float64, a align, leading float64) {
+	if l.y < margin {
+		l.newPage(true)
+	}
+	l.text(chars, indent, l.y, a)
+	l.y -= leading
+}
+
+// blank advances the cursor by n double-spaced lines
+func (l *layout) blank(n int) {
+	l.y -= float64(n) * lineHeight
+}
+
+// paragraph wraps and places a double-spaced paragraph indented to suit its context: body text
+// gets a first-line indent, quotes are indented on both sides, and list items hang from their marker
+func (l *layout) paragraph(chars []char, ctx md.BlockContext) {
+	left := (ctx.QuoteDepth + ctx.ListDepth) * indentChars
+	right := ctx.QuoteDepth * indentChars
+	first := 0
+	if ctx.Marker != "" {
+		marker := plain(ctx.Marker + " ")
+		for len(marker) < 3 {
+			marker = append(marker, char{b: ' '})
+		}
+		chars = append(marker, chars...)
+		first = -len(marker)
+	} else if left == 0 {
+		first = indentChars
+	}
+
+	for i, line := range wrap(chars, lineChars-left-right, first) {
+		indent := left
+		if i == 0 {
+			indent += first
+		}
+		l.line(line, float64(indent)*charWidth, alignLeft, lineHeight)
+	}
+}
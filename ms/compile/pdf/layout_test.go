@@ -0,0 +1,25 @@
+package pdf
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestPageNumbers(t *testing.T) {
+	l := &layout{header: "Writer / TITLE / "}
+	l.newPage(false)
+	l.newPage(true)
+	l.newPage(true)
+
+	// the title page has no header, and the page after it is page 1
+	if strings.Contains(l.pages[0].String(), "TITLE") {
+		t.Errorf("expected no header on the title page:\n%s", l.pages[0].String())
+	}
+	for i, page := range l.pages[1:] {
+		expected := "(Writer / TITLE / " + strconv.Itoa(i+1) + ") Tj"
+		if !strings.Contains(page.String(), expected) {
+			t.Errorf("expected %q on page %d:\n%s", expected, i+2, page.String())
+		}
+	}
+}
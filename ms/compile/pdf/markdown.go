@@ -0,0 +1,71 @@
+package pdf
+
+import (
+	"github.com/gomarkdown/markdown/ast"
+	"gwcoffey/otis/ms/compile/md"
+	"io"
+	"strings"
+)
+
+// renderer lays out a scene's markdown AST; it places text directly on the layout's pages rather
+// than writing to the renderer output
+type renderer struct {
+	layout *layout
+	// sceneBreak is the text written for a scene break within the scene
+	sceneBreak string
+	// style is the styling applied to text at the current point in the walk
+	style char
+	// chars collects the text of the current paragraph
+	chars []char
+}
+
+func (r *renderer) append(text string) {
+	for _, ch := range strings.ReplaceAll(text, "\n", " ") {
+		c := r.style
+		c.b = encode(ch)
+		r.chars = append(r.chars, c)
+	}
+}
+
+func (r *renderer) RenderNode(_ io.Writer, node ast.Node, entering bool) ast.WalkStatus {
+	if md.IsSceneBreak(node) {
+		if entering {
+			r.layout.line(plain(r.sceneBreak), 0, alignCenter, lineHeight)
+		}
+		return ast.SkipChildren
+	}
+
+	switch n := node.(type) {
+	case *ast.Text, *ast.Code, *ast.HTMLSpan:
+		r.append(string(n.AsLeaf().Literal))
+	case *ast.Emph:
+		r.style.underline = entering
+	case *ast.Strong:
+		r.style.bold = entering
+	case *ast.Del:
+		r.style.strike = entering
+	case *ast.Hardbreak:
+		r.chars = append(r.chars, char{b: '\n'})
+	case *ast.Paragraph:
+		if entering {
+			r.chars = nil
+		} else {
+			r.layout.paragraph(r.chars, md.Context(n))
+		}
+	case *ast.Heading:
+		if entering {
+			r.layout.line(plain(md.PlainText(n)), 0, alignCenter, lineHeight)
+		}
+		return ast.SkipChildren
+	case *ast.CodeBlock:
+		ctx := md.Context(n)
+		for _, line := range strings.Split(strings.TrimSuffix(string(n.Literal), "\n"), "\n") {
+			r.layout.line(plain(line), float64((ctx.QuoteDepth+ctx.ListDepth)*indentChars)*charWidth, alignLeft, lineHeight)
+		}
+	}
+	return ast.GoToNext
+}
+
+func (r *renderer) RenderHeader(_ io.Writer, _ ast.Node) {}
+
+func (r *renderer) RenderFooter(_ io.Writer, _ ast.Node) {}
@@ -0,0 +1,37 @@
+package pdf
+
+import (
+	"github.com/gomarkdown/markdown"
+	"gwcoffey/otis/ms/compile/md"
+	"strings"
+	"testing"
+)
+
+func render(text string) string {
+	l := &layout{}
+	l.newPage(false)
+	markdown.Render(md.Parse(text), &renderer{layout: l, sceneBreak: "#"})
+	return l.current().String()
+}
+
+func TestRenderEmphasis(t *testing.T) {
+	out := render("a *b* **c** \\*d\\*")
+	for _, expected := range []string{
+		"(a b ) Tj",                         // regular text, including the underlined word
+		"/F2 12 Tf",                         // bold switches to the bold font
+		"(c) Tj",                            // ...for just the bold word
+		"( *d*) Tj",                         // escaped asterisks are literal
+		"122.40 706.00 m 129.60 706.00 l S", // the underline under b
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected %q in output:\n%s", expected, out)
+		}
+	}
+}
+
+func TestRenderSceneBreak(t *testing.T) {
+	out := render("one\n\n#\n\ntwo")
+	if strings.Count(out, "Tj") != 3 || !strings.Contains(out, "(#) Tj") {
+		t.Errorf("expected a centered scene break in output:\n%s", out)
+	}
+}
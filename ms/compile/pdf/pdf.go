@@ -0,0 +1,160 @@
+package pdf
+
+import (
+	"fmt"
+	"github.com/gomarkdown/markdown"
+	ms2 "gwcoffey/otis/ms"
+	"gwcoffey/otis/ms/compile"
+	"gwcoffey/otis/ms/compile/md"
+	"strings"
+	"time"
+)
+
+// writeScene writes a scene break (if needed) and then the scene itself
+func writeScene(scidx int, scene md.Source, opts compile.Options, l *layout) (err error) {
+	if scidx > 0 {
+		l.line(plain(opts.SceneBreak), 0, alignCenter, lineHeight)
+	}
+	doc, err := md.ParseScene(scene)
+	if err != nil {
+		return
+	}
+	markdown.Render(doc, &renderer{layout: l, sceneBreak: opts.SceneBreak})
+	return
+}
+
+// writeSection writes a front or back matter section, which opens like an unnumbered chapter
+func writeSection(section ms2.Section, opts compile.Options, l *layout) error {
+	l.newPage(true)
+	l.y = pageHeight * 2 / 3
+	l.line(plain(section.Title()), 0, alignCenter, lineHeight)
+	l.blank(1)
+	return writeScene(0, section, opts, l)
+}
+
+func writeTitlePage(m ms2.Manuscript, opts compile.Options, l *layout) (err error) {
+	wcount, err := compile.TitleWordCount(m, opts)
+	if err != nil {
+		return
+	}
+
+	l.newPage(false)
+
+	// author name and word count on the first line, then the single-spaced address block
+	l.text(plain(wcount+" words"), 0, l.y, alignRight)
+	l.line(plain(m.AuthorRealName()), 0, alignLeft, fontSize)
+	for _, line := range strings.Split(m.AuthorAddress(), "\n") {
+		l.line(plain(line), 0, alignLeft, fontSize)
+	}
+
+	// title and byline half way down the page
+	l.y = pageHeight / 2
+	l.line(plain(strings.ToUpper(m.Title())), 0, alignCenter, lineHeight)
+	l.line(plain("By "+m.AuthorName()), 0, alignCenter, lineHeight)
+	return
+}
+
+// writeDocument assembles the pages into a PDF with the standard Courier fonts
+func writeDocument(m ms2.Manuscript, l *layout) (pdf []byte, err error) {
+	w := newWriter()
+	catalog := w.reserve()
+	pagesId := w.reserve()
+	font := w.reserve()
+	boldFont := w.reserve()
+	info := w.reserve()
+
+	var kids []string
+	for _, page := range l.pages {
+		pageId := w.reserve()
+		contentId := w.reserve()
+		kids = append(kids, fmt.Sprintf("%d 0 R", pageId))
+		w.object(pageId, fmt.Sprintf("<< /Type /Page /Parent %d 0 R /Contents %d 0 R >>", pagesId, contentId))
+		// underlines are drawn with a thin stroke
+		if err = w.stream(contentId, []byte("0.6 w\n"+page.String())); err != nil {
+			return
+		}
+	}
+
+	w.object(catalog, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pagesId))
+	w.object(pagesId, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 %d 0 R /F2 %d 0 R >> >> >>",
+		strings.Join(kids, " "), len(kids), pageWidth, pageHeight, font, boldFont))
+	w.object(font, "<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+	w.object(boldFont, "<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>")
+	w.object(info, fmt.Sprintf("<< /Title (%s) /Author (%s) /Creator (otis) /CreationDate (D:%s) >>",
+		escapeString(plain(m.Title())), escapeString(plain(m.AuthorName())), time.Now().UTC().Format("20060102150405Z")))
+
+	pdf = w.finish(catalog, info)
+	return
+}
+
+// ManuscriptToPdf renders the manuscript directly to a PDF in standard manuscript format, with no
+// dependency on an external typesetting system
+func ManuscriptToPdf(m ms2.Manuscript, opts compile.Options) (pdf []byte, err error) {
+	l := &layout{header: fmt.Sprintf("%s / %s / ", m.AuthorSurname(), strings.ToUpper(m.RunningTitle()))}
+
+	if opts.TitlePage {
+		err = writeTitlePage(m, opts, l)
+		if err != nil {
+			return
+		}
+	}
+
+	for _, section := range compile.FrontMatter(m, opts) {
+		if err = writeSection(section, opts, l); err != nil {
+			return
+		}
+	}
+
+	// content
+	if len(m.Chapters()) > 0 {
+		for _, chapter := range m.Chapters() {
+			if part := compile.PartStartingWith(m, chapter); part != nil {
+				// parts get a page of their own, with the heading centered halfway down
+				l.newPage(true)
+				l.y = pageHeight / 2
+				label, title := compile.PartHeading(part)
+				l.line(plain(label), 0, alignCenter, lineHeight)
+				if title != "" {
+					l.line(plain(title), 0, alignCenter, lineHeight)
+				}
+			}
+
+			// chapters open a third of the way down a new page
+			l.newPage(true)
+			l.y = pageHeight * 2 / 3
+			label, title := chapter.Heading(opts.ChapterHeading)
+			if label != "" {
+				l.line(plain(label), 0, alignCenter, lineHeight)
+			}
+			l.line(plain(title), 0, alignCenter, lineHeight)
+			l.blank(1)
+
+			for scidx, scene := range chapter.Scenes() {
+				err = writeScene(scidx, scene, opts, l)
+				if err != nil {
+					return
+				}
+			}
+		}
+	} else { // no chapters
+		l.newPage(true)
+		for scidx, scene := range m.Scenes() {
+			err = writeScene(scidx, scene, opts, l)
+			if err != nil {
+				return
+			}
+		}
+	}
+
+	// end marker
+	l.line(plain("# # # # #"), 0, alignCenter, lineHeight)
+
+	// back matter follows the end of the story
+	for _, section := range compile.BackMatter(m, opts) {
+		if err = writeSection(section, opts, l); err != nil {
+			return
+		}
+	}
+
+	return writeDocument(m, l)
+}
@@ -0,0 +1,130 @@
+package pdf
+
+import (
+	"strings"
+)
+
+// winAnsiExtras maps the characters in the 0x80-0x9f range of WinAnsiEncoding, which is the
+// only place it differs from Latin-1
+var winAnsiExtras = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87, 'ˆ': 0x88,
+	'‰': 0x89, 'Š': 0x8a, '‹': 0x8b, 'Œ': 0x8c, 'Ž': 0x8e, '‘': 0x91, '’': 0x92, '“': 0x93,
+	'”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '˜': 0x98, '™': 0x99, 'š': 0x9a, '›': 0x9b,
+	'œ': 0x9c, 'ž': 0x9e, 'Ÿ': 0x9f,
+}
+
+// char is one encoded character along with its styling; a newline char is a hard line break
+type char struct {
+	b         byte
+	underline bool
+	bold      bool
+	strike    bool
+}
+
+// encode converts a rune to its WinAnsiEncoding byte, substituting '?' for anything the standard
+// fonts can't display
+func encode(r rune) byte {
+	if r == '\t' {
+		return ' '
+	} else if r < 0x80 || (r >= 0xa0 && r <= 0xff) {
+		return byte(r)
+	} else if b, ok := winAnsiExtras[r]; ok {
+		return b
+	}
+	return '?'
+}
+
+// plain converts text with no styling into chars
+func plain(text string) (chars []char) {
+	for _, r := range text {
+		chars = append(chars, char{b: encode(r)})
+	}
+	return
+}
+
+// escapeString makes encoded text safe to insert into a PDF string literal
+func escapeString(chars []char) string {
+	builder := strings.Builder{}
+	for _, c := range chars {
+		switch c.b {
+		case '(', ')', '\\':
+			builder.WriteByte('\\')
+			builder.WriteByte(c.b)
+		default:
+			builder.WriteByte(c.b)
+		}
+	}
+	return builder.String()
+}
+
+// wrap breaks chars into lines of at most width characters, breaking at spaces when possible and
+// always at hard line breaks. The first line is shortened by firstIndent, which may be negative
+// for a hanging indent.
+func wrap(chars []char, width int, firstIndent int) (lines [][]char) {
+	available := width - firstIndent
+	start := 0
+	for i := 0; i <= len(chars); i++ {
+		if i == len(chars) || chars[i].b == '\n' {
+			segment := wrapSegment(chars[start:i], available, width)
+			if len(segment) == 0 {
+				segment = [][]char{nil}
+			}
+			lines = append(lines, segment...)
+			available = width
+			start = i + 1
+		}
+	}
+	return
+}
+
+// wrapSegment breaks chars with no hard line breaks into lines, the first of which may have a
+// different width than the rest
+func wrapSegment(chars []char, firstWidth int, width int) (lines [][]char) {
+	var words [][]char
+	start := -1
+	for i, c := range chars {
+		if c.b == ' ' {
+			if start >= 0 {
+				words = append(words, chars[start:i])
+				start = -1
+			}
+		} else if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		words = append(words, chars[start:])
+	}
+
+	var line []char
+	available := firstWidth
+	for _, word := range words {
+		for len(word) > 0 {
+			needed := len(word)
+			if len(line) > 0 {
+				needed++
+			}
+			if len(line)+needed <= available {
+				if len(line) > 0 {
+					// the space between words carries their shared styling so underlines are continuous
+					prev := line[len(line)-1]
+					line = append(line, char{b: ' ', underline: prev.underline && word[0].underline, strike: prev.strike && word[0].strike})
+				}
+				line = append(line, word...)
+				word = nil
+			} else if len(line) == 0 {
+				// the word alone is longer than a line, so it has to be split
+				line = append(line, word[:available]...)
+				word = word[available:]
+			} else {
+				lines = append(lines, line)
+				line = nil
+				available = width
+			}
+		}
+	}
+	if len(line) > 0 {
+		lines = append(lines, line)
+	}
+	return
+}
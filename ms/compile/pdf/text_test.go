@@ -0,0 +1,44 @@
+package pdf
+
+import "testing"
+
+func TestEncode(t *testing.T) {
+	expectEncoded(t, 'a', 'a')
+	expectEncoded(t, 'é', 0xe9)
+	expectEncoded(t, '’', 0x92)
+	expectEncoded(t, '—', 0x97)
+	expectEncoded(t, '世', '?')
+}
+
+func expectEncoded(t *testing.T, r rune, expected byte) {
+	if actual := encode(r); actual != expected {
+		t.Errorf("expected %q to encode as %x but got %x", r, expected, actual)
+	}
+}
+
+func TestEscapeString(t *testing.T) {
+	if expected, actual := `a\(b\)c\\`, escapeString(plain(`a(b)c\`)); expected != actual {
+		t.Errorf("expected %v but got %v", expected, actual)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	expectWrap(t, "aaa bbb ccc", 7, 0, []string{"aaa bbb", "ccc"})
+	expectWrap(t, "aaa bbb ccc", 7, 2, []string{"aaa", "bbb ccc"})
+	expectWrap(t, "aaa   bbb", 20, 0, []string{"aaa bbb"})
+	expectWrap(t, "aaaaaaaaaa b", 4, 0, []string{"aaaa", "aaaa", "aa b"})
+	expectWrap(t, "aa bb cc dd", 5, -3, []string{"aa bb cc", "dd"})
+	expectWrap(t, "aa\nbb cc", 5, 0, []string{"aa", "bb cc"})
+}
+
+func expectWrap(t *testing.T, text string, width int, firstIndent int, expected []string) {
+	lines := wrap(plain(text), width, firstIndent)
+	if len(lines) != len(expected) {
+		t.Fatalf("expected %d lines but got %d", len(expected), len(lines))
+	}
+	for i, line := range lines {
+		if escapeString(line) != expected[i] {
+			t.Errorf("expected line %d to be %q but got %q", i, expected[i], escapeString(line))
+		}
+	}
+}
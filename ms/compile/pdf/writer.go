@@ -0,0 +1,63 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+)
+
+// writer assembles the low-level structure of a PDF file: numbered objects, a cross-reference
+// table, and a trailer. Object numbers are assigned in the order objects are reserved.
+type writer struct {
+	out     bytes.Buffer
+	offsets []int
+}
+
+func newWriter() *writer {
+	w := &writer{}
+	w.out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	return w
+}
+
+// reserve allocates an object number so objects can refer to one another before they are written
+func (w *writer) reserve() int {
+	w.offsets = append(w.offsets, -1)
+	return len(w.offsets)
+}
+
+// object writes the body of a previously reserved object
+func (w *writer) object(id int, body string) {
+	w.offsets[id-1] = w.out.Len()
+	w.out.WriteString(fmt.Sprintf("%d 0 obj\n%s\nendobj\n", id, body))
+}
+
+// stream writes a previously reserved object as a compressed stream
+func (w *writer) stream(id int, content []byte) (err error) {
+	compressed := bytes.Buffer{}
+	zw := zlib.NewWriter(&compressed)
+	if _, err = zw.Write(content); err != nil {
+		return
+	}
+	if err = zw.Close(); err != nil {
+		return
+	}
+
+	w.offsets[id-1] = w.out.Len()
+	w.out.WriteString(fmt.Sprintf("%d 0 obj\n<< /Length %d /Filter /FlateDecode >>\nstream\n", id, compressed.Len()))
+	w.out.Write(compressed.Bytes())
+	w.out.WriteString("\nendstream\nendobj\n")
+	return
+}
+
+// finish writes the cross-reference table and trailer and returns the complete file
+func (w *writer) finish(root int, info int) []byte {
+	xref := w.out.Len()
+	w.out.WriteString(fmt.Sprintf("xref\n0 %d\n", len(w.offsets)+1))
+	w.out.WriteString("0000000000 65535 f \n")
+	for _, offset := range w.offsets {
+		w.out.WriteString(fmt.Sprintf("%010d 00000 n \n", offset))
+	}
+	w.out.WriteString(fmt.Sprintf("trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\n", len(w.offsets)+1, root, info))
+	w.out.WriteString(fmt.Sprintf("startxref\n%d\n%%%%EOF\n", xref))
+	return w.out.Bytes()
+}
@@ -0,0 +1,79 @@
+package rtf
+
+import (
+	"fmt"
+	"github.com/gomarkdown/markdown/ast"
+	"gwcoffey/otis/ms/compile"
+	"gwcoffey/otis/ms/compile/md"
+	"io"
+	"strings"
+)
+
+// renderer renders a scene's markdown AST as RTF paragraphs
+type renderer struct {
+	opts compile.Options
+}
+
+// paragraphStart opens a double-spaced paragraph indented to suit its context: body text gets a
+// first-line indent, quotes are indented on both sides, and list items hang from their marker
+func paragraphStart(ctx md.BlockContext) string {
+	left := 720 * (ctx.QuoteDepth + ctx.ListDepth)
+	right := 720 * ctx.QuoteDepth
+	switch {
+	case ctx.Marker != "":
+		return fmt.Sprintf(`{\pard\li%d\ri%d\fi-360\tx%d\sl480\slmult1\ql %s\tab `, left, right, left, escapeRtf(ctx.Marker))
+	case left > 0:
+		return fmt.Sprintf(`{\pard\li%d\ri%d\sl480\slmult1\ql `, left, right)
+	default:
+		return `{\pard\fi720\sl480\slmult1\ql `
+	}
+}
+
+func (r *renderer) RenderNode(w io.Writer, node ast.Node, entering bool) ast.WalkStatus {
+	write := func(s string) {
+		_, _ = io.WriteString(w, s)
+	}
+
+	if md.IsSceneBreak(node) {
+		if entering {
+			write(sceneBreak(r.opts))
+		}
+		return ast.SkipChildren
+	}
+
+	switch n := node.(type) {
+	case *ast.Text, *ast.Code, *ast.HTMLSpan:
+		write(escapeRtf(string(n.AsLeaf().Literal)))
+	case *ast.Emph:
+		write(choose(entering, `{\ul `, `}`))
+	case *ast.Strong:
+		write(choose(entering, `{\b `, `}`))
+	case *ast.Del:
+		write(choose(entering, `{\strike `, `}`))
+	case *ast.Hardbreak:
+		write(`\line `)
+	case *ast.Paragraph:
+		write(choose(entering, paragraphStart(md.Context(n)), "\\par}\n"))
+	case *ast.Heading:
+		if entering {
+			write(`{\pard\sl480\slmult1\qc ` + escapeRtf(md.PlainText(n)) + "\\par}\n")
+		}
+		return ast.SkipChildren
+	case *ast.CodeBlock:
+		for _, line := range strings.Split(strings.TrimSuffix(string(n.Literal), "\n"), "\n") {
+			write(`{\pard\sl480\slmult1\ql ` + escapeRtf(line) + "\\par}\n")
+		}
+	}
+	return ast.GoToNext
+}
+
+func (r *renderer) RenderHeader(_ io.Writer, _ ast.Node) {}
+
+func (r *renderer) RenderFooter(_ io.Writer, _ ast.Node) {}
+
+func choose(entering bool, enter string, exit string) string {
+	if entering {
+		return enter
+	}
+	return exit
+}
@@ -2,43 +2,32 @@ package rtf
 
 import (
 	"fmt"
+	"github.com/gomarkdown/markdown"
 	ms2 "gwcoffey/otis/ms"
+	"gwcoffey/otis/ms/compile"
+	"gwcoffey/otis/ms/compile/md"
 	"strings"
 )
 
-// toRtfText prepares text for insertion into RTF; it:
-// - reduces consecutive newlines to a single newline and escapes it
-// - converts *emphasis* to underlines
+// escapeRtf prepares text for insertion into RTF; it:
+// - turns newlines into spaces (line structure comes from the markdown, not the source text)
+// - escapes RTF control characters
 // - escapes non-7bit-ascii characters,
-func toRtfText(text string) string {
+func escapeRtf(text string) string {
 	builder := strings.Builder{}
-	inNewline := false
-	inEmphasis := false
 	for _, r := range text {
 		if r == '\n' {
-			if !inNewline {
-				builder.WriteString("\\\n")
-			}
-			inNewline = true
-		} else if r == '*' {
-			if inEmphasis {
-				builder.WriteString(`\ul0}`)
-				inEmphasis = false
-			} else {
-				builder.WriteString(`{\ul `)
-				inEmphasis = true
-			}
+			builder.WriteRune(' ')
+		} else if r == '\\' || r == '{' || r == '}' {
+			builder.WriteRune('\\')
+			builder.WriteRune(r)
 		} else if r <= 127 {
-			inNewline = false
 			builder.WriteRune(r)
-		} else if r <= 256 {
-			inNewline = false
+		} else if r <= 255 {
 			builder.WriteString(fmt.Sprintf("\\'%x", r))
 		} else if r <= 32768 {
-			inNewline = false
 			builder.WriteString(fmt.Sprintf("\\uc1\\u%d*", r))
 		} else {
-			inNewline = false
 			builder.WriteString(fmt.Sprintf("\\uc1\\u%d*", r-65536))
 		}
 	}
@@ -46,39 +35,59 @@ func toRtfText(text string) string {
 }
 
 // writeScene writes a scene break (if needed) and then the scene itself
-func writeScene(scidx int, scene ms2.Scene, out *strings.Builder) (err error) {
-	var text string
+func writeScene(scidx int, scene md.Source, opts compile.Options, out *strings.Builder) (err error) {
 	if scidx > 0 {
 		// output scene break
-		out.WriteString(`{\pard\sl480\slmult1\qc #\par}`)
+		out.WriteString(sceneBreak(opts))
 	}
-	out.WriteString(`{\pard\fi720\sl480\slmult1\ql `)
-	out.WriteString("\n")
-	text, err = scene.Text()
+	doc, err := md.ParseScene(scene)
 	if err != nil {
 		return
 	}
-	out.WriteString(toRtfText(text))
-	out.WriteString(`\par}`)
-	out.WriteString("\n")
+	out.Write(markdown.Render(doc, &renderer{opts: opts}))
 	return
 }
 
-func ManuscriptToHtml(m ms2.Manuscript) (rtf string, err error) {
-	wcount, err := ms2.ApproximateWordCount(m)
-	if err != nil {
+// writeEpigraph writes a chapter's epigraph (if it has one) as an indented italic paragraph, with
+// the attribution flush right below it
+func writeEpigraph(chapter ms2.Chapter, out *strings.Builder) {
+	if chapter.Epigraph() == "" {
 		return
 	}
+	var lines []string
+	for _, line := range strings.Split(chapter.Epigraph(), "\n") {
+		lines = append(lines, escapeRtf(line))
+	}
+	out.WriteString(`{\pard\li1440\ri1440\sl480\slmult1\ql\i ` + strings.Join(lines, `\line `) + `\i0\par}` + "\n")
+	if chapter.EpigraphAttribution() != "" {
+		out.WriteString(`{\pard\li1440\ri1440\sl480\slmult1\qr ` + escapeRtf("— "+chapter.EpigraphAttribution()) + `\par}` + "\n")
+	}
+	out.WriteString(`{\pard\sl480\slmult1\par}` + "\n")
+}
 
-	out := strings.Builder{}
-	// start doc ansi charset
-	out.WriteString(`{\rtf1\ansi`)
-	// single font in table, courier new
-	out.WriteString(`{\fonttbl\f0\fmodern\fcharset0 CourierNewPSMT;}`)
-	// 1 inch margins
-	out.WriteString(`\margl1440\margr1440`)
-	// courier new 12pt throughout
-	out.WriteString(`\f0\fs24`)
+// writeSection writes a front or back matter section, headed like an unnumbered chapter; the heading
+// is a group of its own so its centering doesn't carry over into the text
+func writeSection(section ms2.Section, opts compile.Options, out *strings.Builder) (err error) {
+	out.WriteString(`{\pard\sl480\slmult1\qc `)
+	out.WriteString("\\\n\\\n\\\n\\\n")
+	if section.Title() != "" {
+		out.WriteString(escapeRtf(section.Title()) + "\\\n\\\n\\\n")
+	}
+	out.WriteString("}\n")
+	return writeScene(0, section, opts, out)
+}
+
+// sceneBreak outputs a centered scene break paragraph
+func sceneBreak(opts compile.Options) string {
+	return `{\pard\sl480\slmult1\qc ` + escapeRtf(opts.SceneBreak) + "\\par}\n"
+}
+
+// writeTitlePage writes the contact block, word count, title, and byline, followed by a page break
+func writeTitlePage(m ms2.Manuscript, opts compile.Options, out *strings.Builder) (err error) {
+	wcount, err := compile.TitleWordCount(m, opts)
+	if err != nil {
+		return
+	}
 
 	// paragraph with right-aligned tab stop at 9360
 	out.WriteString(`\pard\tqr\tx9360`)
@@ -101,8 +110,30 @@ func ManuscriptToHtml(m ms2.Manuscript) (rtf string, err error) {
 	out.WriteString("\\\n")
 	out.WriteString("By " + m.AuthorName())
 
-	// start a new section with header
+	// start a new section
 	out.WriteString(`\sect\sectd\sbknone\page`)
+	return
+}
+
+func ManuscriptToHtml(m ms2.Manuscript, opts compile.Options) (rtf string, err error) {
+	out := strings.Builder{}
+	// start doc ansi charset
+	out.WriteString(`{\rtf1\ansi`)
+	// single font in table, courier new
+	out.WriteString(`{\fonttbl\f0\fmodern\fcharset0 CourierNewPSMT;}`)
+	// 1 inch margins
+	out.WriteString(`\margl1440\margr1440`)
+	// courier new 12pt throughout
+	out.WriteString(`\f0\fs24`)
+
+	if opts.TitlePage {
+		err = writeTitlePage(m, opts, &out)
+		if err != nil {
+			return
+		}
+	}
+
+	// page header
 	out.WriteString(`{\header\pard\f0\fs24\qr `)
 	out.WriteString(m.AuthorSurname())
 	out.WriteString(" / ")
@@ -110,23 +141,49 @@ func ManuscriptToHtml(m ms2.Manuscript) (rtf string, err error) {
 	out.WriteString(` / \chpgn`)
 	out.WriteString(` \par}`)
 
+	// front matter, each section on its own page
+	for _, section := range compile.FrontMatter(m, opts) {
+		if err = writeSection(section, opts, &out); err != nil {
+			return
+		}
+		out.WriteString("\\page\n")
+	}
+
 	// content
 	if len(m.Chapters()) > 0 {
 		for chidx, chapter := range m.Chapters() {
 			if chidx > 0 {
 				out.WriteString("\\page\n")
 			}
-			// paragraph double-spaced centered
+			if part := compile.PartStartingWith(m, chapter); part != nil {
+				// the part gets a page of its own, with the label and title centered
+				label, title := compile.PartHeading(part)
+				out.WriteString(`\pard\sl480\slmult1\qc `)
+				out.WriteString("\\\n\\\n\\\n\\\n\\\n\\\n\\\n\\\n")
+				out.WriteString(escapeRtf(label) + "\\\n")
+				if title != "" {
+					out.WriteString(escapeRtf(title) + "\\\n")
+				}
+				out.WriteString("\\page\n")
+			}
+			// paragraph double-spaced centered, a third of the way down the page
 			out.WriteString(`\pard\sl480\slmult1\qc `)
-			if chapter.Number() != nil {
+			out.WriteString("\\\n\\\n\\\n\\\n")
+			label, title := chapter.Heading(opts.ChapterHeading)
+			if label != "" {
 				// output chapter + number
-				out.WriteString(fmt.Sprintf("\\\n\\\n\\\n\\\nChapter %d\\\n", *chapter.Number()))
+				out.WriteString(escapeRtf(label) + "\\\n")
+			}
+			// output chapter title and subtitle
+			out.WriteString(escapeRtf(title) + "\\\n")
+			if title != "" && chapter.Subtitle() != "" {
+				out.WriteString(escapeRtf(chapter.Subtitle()) + "\\\n")
 			}
-			// output chapter title
-			out.WriteString(chapter.Title() + "\\\n\\\n\\\n")
+			out.WriteString("\\\n\\\n")
+			writeEpigraph(chapter, &out)
 
 			for scidx, scene := range chapter.Scenes() {
-				err = writeScene(scidx, scene, &out)
+				err = writeScene(scidx, scene, opts, &out)
 				if err != nil {
 					return
 				}
@@ -134,7 +191,7 @@ func ManuscriptToHtml(m ms2.Manuscript) (rtf string, err error) {
 		}
 	} else { // no chapters
 		for scidx, scene := range m.Scenes() {
-			err = writeScene(scidx, scene, &out)
+			err = writeScene(scidx, scene, opts, &out)
 			if err != nil {
 				return
 			}
@@ -144,6 +201,14 @@ func ManuscriptToHtml(m ms2.Manuscript) (rtf string, err error) {
 	// output end marker
 	out.WriteString(`\pard\sl480\slmult1\qc # # # # #`)
 
+	// back matter follows the end of the story
+	for _, section := range compile.BackMatter(m, opts) {
+		out.WriteString("\\page\n")
+		if err = writeSection(section, opts, &out); err != nil {
+			return
+		}
+	}
+
 	// terminate RTF
 	out.WriteString("}")
 
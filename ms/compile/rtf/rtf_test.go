@@ -0,0 +1,52 @@
+package rtf
+
+import (
+	"gwcoffey/otis/fixture"
+	"gwcoffey/otis/ms"
+	"gwcoffey/otis/ms/compile"
+	"strings"
+	"testing"
+)
+
+func TestSections(t *testing.T) {
+	m, err := ms.Load(fixture.Path("parts"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rtf, err := ManuscriptToHtml(m, compile.DefaultOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// section headings are groups of their own, so the text after them isn't centered, and an
+	// untitled section has no heading text at all
+	for _, expected := range []string{
+		"{\\pard\\sl480\\slmult1\\qc \\\n\\\n\\\n\\\n}\n{\\pard\\fi720\\sl480\\slmult1\\ql For the staff.",
+		"{\\pard\\sl480\\slmult1\\qc \\\n\\\n\\\n\\\nAbout the Author\\\n\\\n\\\n}\n{\\pard\\fi720\\sl480\\slmult1\\ql Wendy writes.",
+	} {
+		if !strings.Contains(rtf, expected) {
+			t.Errorf("expected %q in\n%s", expected, rtf)
+		}
+	}
+}
+
+func TestSubtitleAndEpigraph(t *testing.T) {
+	m, err := ms.Load(fixture.Path("parts"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rtf, err := ManuscriptToHtml(m, compile.DefaultOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "Chapter 1\\\n100% Effort\\\nIn Which Nothing Goes to Plan\\\n\\\n\\\n" +
+		"{\\pard\\li1440\\ri1440\\sl480\\slmult1\\ql\\i Nothing ventured,\\line nothing gained.\\i0\\par}\n" +
+		"{\\pard\\li1440\\ri1440\\sl480\\slmult1\\qr \\uc1\\u8212* Proverb\\par}\n"
+	if !strings.Contains(rtf, expected) {
+		t.Errorf("expected %q in\n%s", expected, rtf)
+	}
+	if strings.Count(rtf, "\\ql\\i ") != 1 {
+		t.Errorf("expected an epigraph for the first chapter only in\n%s", rtf)
+	}
+}
@@ -1,8 +1,6 @@
 package tex
 
 import (
-	"fmt"
-	"regexp"
 	"strings"
 )
 
@@ -28,10 +26,6 @@ var textEscapes = strings.NewReplacer(
 	`}`, `\}`,
 )
 
-var emphasisPattern = regexp.MustCompile(`\*(.+?)\*`)
-var blockquotePattern = regexp.MustCompile(`^>.*(?:\n>.*)*`)
-var blockquoteCleanerPattern = regexp.MustCompile(`^>\s+`)
-
 // escapeOptionalArg textEscapes text for an argument to a command
 func escapeOptionalArg(text string) string {
 	return optionalCommandEscapes.Replace(text)
@@ -48,20 +42,6 @@ func escapeText(text string) string {
 	return textEscapes.Replace(text)
 }
 
-// formatMarkdown converts basic markdown into latex-formatted text. This very simple
-// implementation only supports a small subset of markdown, namely:
-//
-//	*emphasis* 		-> \emph
-//	> blockquotes 	-> \begin{quotation}...\end{quotation}
-func formatMarkdown(text string) string {
-	text = emphasisPattern.ReplaceAllString(text, `\emph{$1}`)
-	text = blockquotePattern.ReplaceAllStringFunc(text, func(match string) string {
-		clean := blockquoteCleanerPattern.ReplaceAllString(match, "")
-		return fmt.Sprintf("\\begin{quotation}\n%s\n\\end{quotation}\n", clean)
-	})
-	return text
-}
-
 // command outputs a latex command with (optional) arguments
 func command(command string, options []string, args []string) string {
 	builder := strings.Builder{}
@@ -1,6 +1,9 @@
 package tex
 
-import "testing"
+import (
+	"gwcoffey/otis/ms/compile"
+	"testing"
+)
 
 func TestNoEscapes(t *testing.T) {
 	expectEscape(t, `clean`, `clean`)
@@ -31,14 +34,27 @@ func expectEscape(t *testing.T, unescaped string, expected string) {
 }
 
 func TestFormatMarkdown(t *testing.T) {
-	expectFormat(t, `clean`, `clean`)
-	expectFormat(t, `this *is* neat`, `this \emph{is} neat`)
-	expectFormat(t, `*this is neat*`, `\emph{this is neat}`)
+	expectFormat(t, `clean`, "clean\n")
+	expectFormat(t, `this *is* neat`, "this \\emph{is} neat\n")
+	expectFormat(t, `*this is neat*`, "\\emph{this is neat}\n")
 	expectFormat(t, `> a blockquote`, "\\begin{quotation}\na blockquote\n\\end{quotation}\n")
+	expectFormat(t, `this **is** neat`, "this \\textbf{is} neat\n")
+	expectFormat(t, "one\n\ntwo", "one\n\ntwo\n")
+}
+
+func TestFormatMarkdownEscapes(t *testing.T) {
+	expectFormat(t, `a \*literal\* & more`, "a *literal* \\& more\n")
+	expectFormat(t, "a `co*de_x` b", "a \\texttt{co*de\\_x} b\n")
+	expectFormat(t, "line one\\\nline two", "line one\\\\\nline two\n")
+}
+
+func TestFormatMarkdownLists(t *testing.T) {
+	expectFormat(t, "- a\n- b", "\\begin{itemize}\n\\item a\n\\item b\n\\end{itemize}\n")
+	expectFormat(t, "3. a\n4. b", "\\begin{enumerate}\n\\item[3.] a\n\\item[4.] b\n\\end{enumerate}\n")
 }
 
 func expectFormat(t *testing.T, unformatted string, expected string) {
-	it := formatMarkdown(unformatted)
+	it := formatMarkdown(unformatted, compile.DefaultOptions())
 	if it != expected {
 		t.Error("expected", it, "to equal", expected)
 	}
@@ -0,0 +1,95 @@
+package tex
+
+import (
+	"github.com/gomarkdown/markdown"
+	"github.com/gomarkdown/markdown/ast"
+	"gwcoffey/otis/ms/compile"
+	"gwcoffey/otis/ms/compile/md"
+	"io"
+	"strings"
+)
+
+// renderer renders a scene's markdown AST as latex
+type renderer struct {
+	opts compile.Options
+}
+
+func (r *renderer) RenderNode(w io.Writer, node ast.Node, entering bool) ast.WalkStatus {
+	write := func(s string) {
+		_, _ = io.WriteString(w, s)
+	}
+
+	if md.IsSceneBreak(node) {
+		if entering {
+			write(sceneBreak(r.opts))
+		}
+		return ast.SkipChildren
+	}
+
+	switch n := node.(type) {
+	case *ast.Text, *ast.HTMLSpan:
+		write(escapeText(string(n.AsLeaf().Literal)))
+	case *ast.Code:
+		write(`\texttt{` + escapeText(string(n.Literal)) + `}`)
+	case *ast.Emph:
+		write(choose(entering, `\emph{`, `}`))
+	case *ast.Strong:
+		write(choose(entering, `\textbf{`, `}`))
+	case *ast.Hardbreak:
+		write("\\\\\n")
+	case *ast.Paragraph:
+		if entering && ast.GetPrevNode(n) != nil {
+			// blank line between consecutive blocks
+			write("\n")
+		} else if !entering {
+			write("\n")
+		}
+	case *ast.Heading:
+		if entering {
+			write("\\begin{center}\n" + escapeText(md.PlainText(n)) + "\n\\end{center}\n")
+		}
+		return ast.SkipChildren
+	case *ast.BlockQuote:
+		write(choose(entering, "\\begin{quotation}\n", "\\end{quotation}\n"))
+	case *ast.List:
+		env := "itemize"
+		if n.ListFlags&ast.ListTypeOrdered != 0 {
+			env = "enumerate"
+		}
+		write(choose(entering, "\\begin{"+env+"}\n", "\\end{"+env+"}\n"))
+	case *ast.ListItem:
+		if entering && n.ListFlags&ast.ListTypeOrdered != 0 {
+			// give the number explicitly so lists that start part way through keep their numbers
+			write(`\item[` + escapeOptionalArg(md.ListMarker(n)) + `] `)
+		} else if entering {
+			write(`\item `)
+		}
+	case *ast.CodeBlock:
+		for _, line := range strings.Split(strings.TrimSuffix(string(n.Literal), "\n"), "\n") {
+			write(`\texttt{` + escapeText(line) + "}\\\\\n")
+		}
+	}
+	return ast.GoToNext
+}
+
+func (r *renderer) RenderHeader(_ io.Writer, _ ast.Node) {}
+
+func (r *renderer) RenderFooter(_ io.Writer, _ ast.Node) {}
+
+func choose(entering bool, enter string, exit string) string {
+	if entering {
+		return enter
+	}
+	return exit
+}
+
+// formatMarkdown converts markdown into latex-formatted text, escaping the text itself as it goes:
+//
+//	*emphasis*      -> \emph
+//	**strong**      -> \textbf
+//	`code`          -> \texttt
+//	> blockquotes   -> \begin{quotation}...\end{quotation}
+//	- lists         -> \begin{itemize}...\end{itemize} (or enumerate)
+func formatMarkdown(text string, opts compile.Options) string {
+	return string(markdown.Render(md.Parse(text), &renderer{opts: opts}))
+}
@@ -3,25 +3,67 @@ package tex
 import (
 	_ "embed"
 	ms2 "gwcoffey/otis/ms"
+	"gwcoffey/otis/ms/compile"
+	"gwcoffey/otis/ms/compile/md"
 	"strings"
 )
 
-func writeScene(scidx int, scene ms2.Scene, out *strings.Builder) (err error) {
+// sceneBreak outputs a scene break, using the sffms scene break unless the options call for
+// something other than the standard "#"
+func sceneBreak(opts compile.Options) string {
+	if opts.SceneBreak == "#" {
+		return command("newscene", nil, nil)
+	}
+	return "\\begin{center}\n" + escapeText(opts.SceneBreak) + "\n\\end{center}\n"
+}
+
+func writeScene(scidx int, scene md.Source, opts compile.Options, out *strings.Builder) (err error) {
 	if scidx > 0 {
-		out.WriteString(command("newscene", nil, nil))
+		out.WriteString(sceneBreak(opts))
 	}
 	text, err := scene.Text()
 	if err != nil {
 		return
 	}
-	out.WriteString(wrap(formatMarkdown(escapeText(text))))
+	out.WriteString(wrap(formatMarkdown(text, opts)))
 	if !strings.HasSuffix(text, "\n") {
 		out.WriteString("\n")
 	}
 	return
 }
 
-func ManuscriptToTex(m ms2.Manuscript) (tex string, err error) {
+// writeEpigraph writes a chapter's epigraph (if it has one) in a quote environment, with the
+// attribution set flush right below it
+func writeEpigraph(chapter ms2.Chapter, out *strings.Builder) {
+	if chapter.Epigraph() == "" {
+		return
+	}
+	// each line ends with \\, so blank lines (which would end the paragraph inside \emph) are dropped
+	var lines []string
+	for _, line := range strings.Split(chapter.Epigraph(), "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, escapeText(line))
+		}
+	}
+	out.WriteString(command("begin", nil, []string{"quote"}))
+	out.WriteString(command("emph", nil, []string{strings.Join(lines, "\n")}))
+	if chapter.EpigraphAttribution() != "" {
+		out.WriteString("\\par\\hfill --- " + escapeText(chapter.EpigraphAttribution()) + "\n")
+	}
+	out.WriteString(command("end", nil, []string{"quote"}))
+}
+
+// writeSection writes a front or back matter section, which starts a page like an unnumbered
+// chapter
+func writeSection(section ms2.Section, opts compile.Options, out *strings.Builder) error {
+	out.WriteString("\n")
+	out.WriteString(command("chapter*", nil, []string{escapeText(section.Title())}))
+	return writeScene(0, section, opts, out)
+}
+
+// ManuscriptToTex converts the manuscript to a latex document using the sffms class. (sffms always
+// produces a title page, so opts.TitlePage has no effect here.)
+func ManuscriptToTex(m ms2.Manuscript, opts compile.Options) (tex string, err error) {
 
 	out := strings.Builder{}
 	out.WriteString(command("documentclass", []string{"novel", "courier"}, []string{"sffms"}))
@@ -35,7 +77,7 @@ func ManuscriptToTex(m ms2.Manuscript) (tex string, err error) {
 	out.WriteString(command("title", nil, []string{m.Title()}))
 	out.WriteString(command("runningtitle", nil, []string{m.RunningTitle()}))
 
-	wcount, err := ms2.ApproximateWordCount(m)
+	wcount, err := compile.TitleWordCount(m, opts)
 	if err != nil {
 		return
 	}
@@ -43,16 +85,31 @@ func ManuscriptToTex(m ms2.Manuscript) (tex string, err error) {
 
 	out.WriteString(command("begin", nil, []string{"document"}))
 
+	for _, section := range compile.FrontMatter(m, opts) {
+		if err = writeSection(section, opts, &out); err != nil {
+			return
+		}
+	}
+
 	if len(m.Chapters()) > 0 {
 		for _, chapter := range m.Chapters() {
 			out.WriteString("\n") // blank line before each chap for better readability
-			if chapter.Number() == nil {
-				out.WriteString(command("chapter*", nil, []string{chapter.Title()}))
-			} else {
-				out.WriteString(command("chapter", nil, []string{chapter.Title()}))
+			if part := compile.PartStartingWith(m, chapter); part != nil {
+				// the label is spelled out to match the other formats, so \part's own numbering is off
+				label, title := compile.PartHeading(part)
+				out.WriteString(command("part*", nil, []string{strings.TrimSpace(escapeText(label) + "\n" + escapeText(title))}))
+			}
+			// like parts, the label comes from the chapter so it matches the other formats, rather
+			// than from sffms's own \chapter numbering
+			label, title := chapter.Heading(opts.ChapterHeading)
+			label, title = escapeText(label), escapeText(title)
+			if title != "" && chapter.Subtitle() != "" {
+				title += "\n" + escapeText(chapter.Subtitle())
 			}
+			out.WriteString(command("chapter*", nil, []string{strings.TrimSpace(label + "\n" + title)}))
+			writeEpigraph(chapter, &out)
 			for i, scene := range chapter.Scenes() {
-				err = writeScene(i, scene, &out)
+				err = writeScene(i, scene, opts, &out)
 				if err != nil {
 					return
 				}
@@ -60,13 +117,19 @@ func ManuscriptToTex(m ms2.Manuscript) (tex string, err error) {
 		}
 	} else { // no chapters
 		for i, scene := range m.Scenes() {
-			err = writeScene(i, scene, &out)
+			err = writeScene(i, scene, opts, &out)
 			if err != nil {
 				return
 			}
 		}
 	}
 
+	for _, section := range compile.BackMatter(m, opts) {
+		if err = writeSection(section, opts, &out); err != nil {
+			return
+		}
+	}
+
 	out.WriteString("\n")
 	out.WriteString(command("end", nil, []string{"document"}))
 
@@ -0,0 +1,68 @@
+package tex
+
+import (
+	"gwcoffey/otis/fixture"
+	"gwcoffey/otis/ms"
+	"gwcoffey/otis/ms/compile"
+	"strings"
+	"testing"
+)
+
+func TestHeadingsAreEscaped(t *testing.T) {
+	m, err := ms.Load(fixture.Path("parts"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tex, err := ManuscriptToTex(m, compile.DefaultOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		"\\part*{PART ONE\\\\\nProfit \\& Loss}\n",
+		"\\chapter*{Chapter 1\\\\\n100\\% Effort\\\\\nIn Which Nothing Goes to Plan}\n",
+		"\\chapter*{Chapter 2\\\\\nAftermath}\n",
+		"\\chapter*{About the Author}\n",
+	} {
+		if !strings.Contains(tex, expected) {
+			t.Errorf("expected %q in\n%s", expected, tex)
+		}
+	}
+}
+
+func TestEpigraph(t *testing.T) {
+	m, err := ms.Load(fixture.Path("parts"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tex, err := ManuscriptToTex(m, compile.DefaultOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "Plan}\n\\begin{quote}\n\\emph{Nothing ventured{,}\\\\\nnothing gained.}\n\\par\\hfill --- Proverb\n\\end{quote}\n"
+	if !strings.Contains(tex, expected) {
+		t.Errorf("expected %q in\n%s", expected, tex)
+	}
+	if strings.Count(tex, "\\begin{quote}") != 1 {
+		t.Errorf("expected an epigraph for the first chapter only in\n%s", tex)
+	}
+}
+
+func TestSubtitleNeedsTitle(t *testing.T) {
+	m, err := ms.Load(fixture.Path("parts"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := compile.DefaultOptions()
+	opts.ChapterHeading = compile.HeadingNumber
+	tex, err := ManuscriptToTex(m, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a subtitle goes with the title, so it isn't shown when the heading has only a number
+	if !strings.Contains(tex, "\\chapter*{Chapter 1}\n") || strings.Contains(tex, "In Which Nothing Goes to Plan") {
+		t.Errorf("expected a heading with only the number in\n%s", tex)
+	}
+}
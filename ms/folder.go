@@ -39,13 +39,13 @@ func (f *folder) PrettyFileName() string {
 }
 
 func (f *folder) Folders() []Folder {
-	return f.node.folders()
+	return f.node.folders(f.manuscript, f)
 }
 
 func (f *folder) Scenes() (scenes []Scene) {
 	for _, child := range f.node.children {
-		if !child.isDir {
-			scenes = append(scenes, &scene{node: child, folder: f})
+		if !child.isDir && f.manuscript.includes(child) {
+			scenes = append(scenes, &scene{node: child, folder: f, manuscript: f.manuscript})
 		}
 	}
 	return
@@ -53,8 +53,8 @@ func (f *folder) Scenes() (scenes []Scene) {
 
 func (f *folder) AllScenes() (scenes []Scene) {
 	f.node.walk(func(node *node) {
-		if !node.isDir {
-			scenes = append(scenes, &scene{node: node, folder: f})
+		if !node.isDir && f.manuscript.includes(node) {
+			scenes = append(scenes, &scene{node: node, folder: f, manuscript: f.manuscript})
 		}
 	})
 	return
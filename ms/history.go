@@ -0,0 +1,35 @@
+package ms
+
+import (
+	"gwcoffey/otis/git"
+	"path/filepath"
+)
+
+// SceneWordCountsAt counts the words in each scene file of the manuscript as of a git revision,
+// keyed by the scene's path relative to the project root
+func SceneWordCountsAt(m Manuscript, rev string) (counts map[string]int, err error) {
+	files, err := git.Files(m.Path(), rev, "manuscript")
+	if err != nil {
+		return
+	}
+
+	var hashes []string
+	for name, hash := range files {
+		if filepath.Ext(name) == ".md" {
+			hashes = append(hashes, hash)
+		}
+	}
+
+	blobs, err := git.ReadBlobs(m.Path(), hashes)
+	if err != nil {
+		return
+	}
+
+	counts = map[string]int{}
+	for name, hash := range files {
+		if filepath.Ext(name) == ".md" {
+			counts[name] = SceneFileWordCount(blobs[hash])
+		}
+	}
+	return
+}
@@ -2,7 +2,9 @@ package ms
 
 import (
 	"fmt"
+	"gwcoffey/otis/oerr"
 	"strings"
+	"time"
 )
 
 type authorMeta struct {
@@ -12,16 +14,36 @@ type authorMeta struct {
 }
 
 type manuscriptMeta struct {
-	Title        string     `yaml:"title"`
-	RunningTitle *string    `yaml:"runningTitle"`
-	Author       authorMeta `yaml:"author"`
-	AddressLines string     `yaml:"address"`
+	Title          string                 `yaml:"title"`
+	RunningTitle   *string                `yaml:"runningTitle"`
+	Author         authorMeta             `yaml:"author"`
+	AddressLines   string                 `yaml:"address"`
+	Profiles       map[string]profileMeta `yaml:"profiles"`
+	TargetWords    *int                   `yaml:"targetWords"`
+	Deadline       *string                `yaml:"deadline"`
+	NumberWidth    *int                   `yaml:"numberWidth"`
+	ChapterNumbers *string                `yaml:"chapterNumbers"`
+	ChapterHeading *string                `yaml:"chapterHeading"`
 }
 
+// deadlineFormat is the layout of the deadline in `otis.yml`
+const deadlineFormat = "2006-01-02"
+
+// defaultNumberWidth is how many digits scene and folder numbers have unless `otis.yml` says
+// otherwise
+const defaultNumberWidth = 2
+
 type manuscript struct {
 	path string
 	meta manuscriptMeta
 	node *node
+	// frontMatter and backMatter are the sections before and after the chapters
+	frontMatter []Section
+	backMatter  []Section
+	// keep decides which scenes are included in a filtered manuscript (nil includes everything)
+	keep func(Scene) bool
+	// warnings are the problems Load noticed that don't stop it
+	warnings []Problem
 }
 
 type Manuscript interface {
@@ -33,9 +55,21 @@ type Manuscript interface {
 	AuthorRealName() string
 	AuthorAddress() string
 	Path() string
+	TargetWords() *int
+	Deadline() *time.Time
+	NumberWidth() int
+	ChapterNumbers() NumberStyle
+	ChapterHeading() HeadingStyle
 	Folders() []Folder
+	Parts() []Part
 	Chapters() []Chapter
 	Scenes() []Scene
+	FrontMatter() []Section
+	BackMatter() []Section
+	Profile(name string) (Profile, error)
+	Filter(keep func(Scene) bool) Manuscript
+	Whole() Manuscript
+	Warnings() []Problem
 }
 
 func (m *manuscript) String() string {
@@ -84,8 +118,69 @@ func (m *manuscript) Path() string {
 	return m.path
 }
 
+// TargetWords returns the word count goal from `otis.yml`, if there is one
+func (m *manuscript) TargetWords() *int {
+	return m.meta.TargetWords
+}
+
+// Deadline returns the date the target word count should be reached, if there is one (the
+// deadline is checked when the manuscript is loaded)
+func (m *manuscript) Deadline() *time.Time {
+	if m.meta.Deadline == nil {
+		return nil
+	}
+	deadline, err := time.ParseInLocation(deadlineFormat, *m.meta.Deadline, time.Local)
+	if err != nil {
+		return nil
+	}
+	return &deadline
+}
+
+// NumberWidth returns how many digits to use when numbering new or moved scenes and folders
+func (m *manuscript) NumberWidth() int {
+	if m.meta.NumberWidth == nil {
+		return defaultNumberWidth
+	}
+	return *m.meta.NumberWidth
+}
+
+// ChapterNumbers returns how chapter numbers are written in headings (the style is checked when
+// the manuscript is loaded)
+func (m *manuscript) ChapterNumbers() NumberStyle {
+	if m.meta.ChapterNumbers == nil {
+		return NumbersArabic
+	}
+	return NumberStyle(strings.ToLower(*m.meta.ChapterNumbers))
+}
+
+// ChapterHeading returns what appears at the top of each chapter, unless a compile profile or
+// option says otherwise
+func (m *manuscript) ChapterHeading() HeadingStyle {
+	if m.meta.ChapterHeading == nil {
+		return HeadingBoth
+	}
+	return HeadingStyle(strings.ToLower(*m.meta.ChapterHeading))
+}
+
 func (m *manuscript) Folders() []Folder {
-	return m.node.folders()
+	return m.node.folders(m, nil)
+}
+
+// Parts returns the parts of the manuscript, numbered from one; a filtered manuscript leaves out
+// parts with no included chapters
+func (m *manuscript) Parts() (parts []Part) {
+	count := 1
+	m.node.walk(func(node *node) {
+		if node.partMeta != nil {
+			p := &part{node: node, manuscript: m, number: count}
+			count++
+			if m.keep == nil || len(p.Chapters()) > 0 {
+				parts = append(parts, p)
+			}
+		}
+	})
+
+	return
 }
 
 func (m *manuscript) Chapters() (chapters []Chapter) {
@@ -95,23 +190,86 @@ func (m *manuscript) Chapters() (chapters []Chapter) {
 		if node.chapterMeta != nil {
 			var number *int
 			if node.chapterMeta.Numbered == nil || *node.chapterMeta.Numbered {
+				// a chapter can set its own number, and the chapters after it count on from there
+				if node.chapterMeta.Number != nil {
+					count = *node.chapterMeta.Number
+				}
 				newNumber := count
 				number = &newNumber
 				count++
 			}
-			chapters = append(chapters, &chapter{node: node, manuscript: m, number: number})
+			// numbers are counted over the whole manuscript, so a filtered manuscript keeps its
+			// chapter numbers even when earlier chapters are left out
+			ch := &chapter{node: node, manuscript: m, number: number}
+			if m.keep == nil || len(ch.Scenes()) > 0 {
+				chapters = append(chapters, ch)
+			}
 		}
 	})
 
 	return
 }
 
+// FrontMatter returns the sections in `frontmatter/`, which come before the chapters (a filter
+// doesn't apply to them)
+func (m *manuscript) FrontMatter() []Section {
+	return m.frontMatter
+}
+
+// BackMatter returns the sections in `backmatter/`, which come after the chapters
+func (m *manuscript) BackMatter() []Section {
+	return m.backMatter
+}
+
 func (m *manuscript) Scenes() (scenes []Scene) {
 	m.node.walk(func(node *node) {
-		if !node.isDir {
+		if !node.isDir && m.includes(node) {
 			scenes = append(scenes, &scene{node: node, manuscript: m})
 		}
 	})
 
 	return
 }
+
+// Profile returns the named compile profile from `otis.yml`
+func (m *manuscript) Profile(name string) (Profile, error) {
+	meta, ok := m.meta.Profiles[name]
+	if !ok {
+		return nil, oerr.ProfileNotFound(name)
+	}
+	return &profile{name: name, meta: meta}, nil
+}
+
+// Filter returns a view of the manuscript that only includes the scenes for which keep returns
+// true. Chapters and folders with no remaining scenes are left out. Filters compose, so filtering
+// a filtered manuscript keeps only the scenes both filters include.
+func (m *manuscript) Filter(keep func(Scene) bool) Manuscript {
+	combined := keep
+	if m.keep != nil {
+		previous := m.keep
+		combined = func(s Scene) bool {
+			return previous(s) && keep(s)
+		}
+	}
+	return &manuscript{path: m.path, meta: m.meta, node: m.node, frontMatter: m.frontMatter, backMatter: m.backMatter, keep: combined, warnings: m.warnings}
+}
+
+// Whole returns the manuscript with any filters removed
+func (m *manuscript) Whole() Manuscript {
+	if m.keep == nil {
+		return m
+	}
+	return &manuscript{path: m.path, meta: m.meta, node: m.node, frontMatter: m.frontMatter, backMatter: m.backMatter, warnings: m.warnings}
+}
+
+// Warnings returns the problems noticed while loading the manuscript that don't stop otis from
+// working with it, like folders whose numbers don't sort in order; commands decide whether to
+// show them
+func (m *manuscript) Warnings() []Problem {
+	return m.warnings
+}
+
+// includes reports whether a scene node passes this manuscript's filter
+func (m *manuscript) includes(n *node) bool {
+	return m.keep == nil || m.keep(&scene{node: n, manuscript: m})
+}
@@ -14,14 +14,84 @@ import (
 	"strings"
 )
 
-func validateManuscript(m Manuscript) (err error) {
-	if len(m.Chapters()) > 0 && m.Scenes()[0].Path() != m.Chapters()[0].Scenes()[0].Path() {
-		err = errors.New(fmt.Sprintf("manuscript %s has scenes before the first chapter", m.Path()))
+func validateManuscript(m *manuscript) (err error) {
+	if len(m.Chapters()) > 0 && len(m.Scenes()) > 0 {
+		// the first chapter with any scenes must start with the first scene (empty chapters are
+		// allowed, if pointless)
+		var first Scene
+		for _, chapter := range m.Chapters() {
+			if len(chapter.Scenes()) > 0 {
+				first = chapter.Scenes()[0]
+				break
+			}
+		}
+		if first == nil || m.Scenes()[0].Path() != first.Path() {
+			err = errors.New(fmt.Sprintf("manuscript %s has scenes before the first chapter", m.Path()))
+		}
+	}
+	if len(m.Parts()) > 0 {
+		// likewise, if there are parts, the first chapter must start the first part
+		var first Chapter
+		for _, part := range m.Parts() {
+			if len(part.Chapters()) > 0 {
+				first = part.Chapters()[0]
+				break
+			}
+		}
+		if len(m.Chapters()) == 0 {
+			err = errors.New(fmt.Sprintf("manuscript %s has parts but no chapters", m.Path()))
+		} else if first == nil || m.Chapters()[0].Path() != first.Path() {
+			err = errors.New(fmt.Sprintf("manuscript %s has chapters before the first part", m.Path()))
+		}
+	}
+	if m.meta.Deadline != nil && m.Deadline() == nil {
+		err = errors.New(fmt.Sprintf("deadline %s in otis.yml is not a date like 2024-12-31", *m.meta.Deadline))
 	}
+	if m.meta.NumberWidth != nil && *m.meta.NumberWidth < 1 {
+		err = errors.New(fmt.Sprintf("numberWidth %d in otis.yml must be at least 1", *m.meta.NumberWidth))
+	}
+	switch m.ChapterNumbers() {
+	case NumbersArabic, NumbersRoman, NumbersWords:
+	default:
+		err = errors.New(fmt.Sprintf("chapterNumbers %s in otis.yml must be arabic, roman, or words", *m.meta.ChapterNumbers))
+	}
+	switch m.ChapterHeading() {
+	case HeadingBoth, HeadingNumber, HeadingTitle:
+	default:
+		err = errors.New(fmt.Sprintf("chapterHeading %s in otis.yml must be both, number, or title", *m.meta.ChapterHeading))
+	}
+	m.node.walk(func(n *node) {
+		if n.chapterMeta != nil && n.chapterMeta.Number != nil && n.chapterMeta.Numbered != nil && !*n.chapterMeta.Numbered {
+			err = errors.New(fmt.Sprintf("chapter %s has a number but is not numbered", n.path))
+		}
+	})
 
 	return
 }
 
+// outOfOrderFolders returns the folders (including the manuscript folder itself) whose items are
+// numbered with different widths, so sorting by name (as otis and most other tools do) puts them
+// in the wrong order; `100-` sorts before `11-`, for example
+func outOfOrderFolders(m *manuscript) (dirs []string) {
+	m.node.walk(func(n *node) {
+		if !n.isDir {
+			return
+		}
+		last := -1
+		for _, child := range n.children {
+			if child.fileNumber < 0 {
+				continue
+			}
+			if child.fileNumber < last {
+				dirs = append(dirs, n.path)
+				return
+			}
+			last = child.fileNumber
+		}
+	})
+	return
+}
+
 func MustLoad(path string) Manuscript {
 	manuscript, err := Load(path)
 	if err != nil {
@@ -48,10 +118,23 @@ func Load(path string) (ms Manuscript, err error) {
 		return
 	}
 
-	ms = &manuscript{path: path, meta: meta, node: node}
-	if err = validateManuscript(ms); err != nil {
+	m := &manuscript{path: path, meta: meta, node: node}
+	if m.frontMatter, err = loadSections(filepath.Join(path, frontMatterDir)); err != nil {
+		return
+	}
+	if m.backMatter, err = loadSections(filepath.Join(path, backMatterDir)); err != nil {
 		return
 	}
+	if err = validateManuscript(m); err != nil {
+		return
+	}
+	for _, dir := range outOfOrderFolders(m) {
+		if rel, rerr := filepath.Rel(path, dir); rerr == nil {
+			dir = rel
+		}
+		m.warnings = append(m.warnings, Problem{Severity: SeverityWarning, Path: dir, Message: "numbers have different widths, so they don't sort in order (use otis renumber to fix them)"})
+	}
+	ms = m
 	return
 }
 
@@ -145,6 +228,20 @@ func WordCount(m Manuscript) (count int, err error) {
 	return
 }
 
+// SceneFileWordCount counts the words in the raw content of a scene file, ignoring its front
+// matter; it counts the same way as WordCount, for scene files that aren't in a loaded manuscript
+func SceneFileWordCount(content []byte) int {
+	_, body := splitFrontMatter(content)
+	return len(strings.Fields(string(body)))
+}
+
+// SplitSceneFile separates the front matter block at the top of a scene file (including its
+// delimiter lines) from the rest of the file; the front matter is empty if there is none
+func SplitSceneFile(content []byte) (frontMatter []byte, body []byte) {
+	_, body = splitFrontMatter(content)
+	return content[:len(content)-len(body)], body
+}
+
 func ApproximateWordCount(m Manuscript) (result string, err error) {
 	count, err := WordCount(m)
 	if err != nil {
@@ -0,0 +1,29 @@
+package ms
+
+import (
+	"gwcoffey/otis/fixture"
+	"slices"
+	"testing"
+)
+
+func TestLoadWarnings(t *testing.T) {
+	m, err := Load(fixture.Path("messy"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 1-scene.md sorts after 03-scene.md
+	expected := []Problem{
+		{SeverityWarning, "manuscript/03-second", "numbers have different widths, so they don't sort in order (use otis renumber to fix them)"},
+	}
+	if warnings := m.Warnings(); !slices.Equal(warnings, expected) {
+		t.Errorf("expected %v, got %v", expected, warnings)
+	}
+	if warnings := m.Filter(func(Scene) bool { return false }).Warnings(); !slices.Equal(warnings, expected) {
+		t.Errorf("expected a filtered manuscript to keep its warnings, got %v", warnings)
+	}
+
+	if m, err = Load(fixture.Path("scenes")); err != nil || len(m.Warnings()) != 0 {
+		t.Errorf("expected no warnings, got %v %v", m, err)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/go-yaml/yaml"
 	"gwcoffey/otis/msfs"
 	"gwcoffey/otis/text"
+	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
@@ -19,6 +20,8 @@ type node struct {
 	isDir       bool
 	path        string
 	chapterMeta *chapterMeta
+	partMeta    *partMeta
+	sceneMeta   *sceneMeta
 	children    []*node
 	content     []byte
 	fileNumber  int
@@ -27,12 +30,34 @@ type node struct {
 // chapterMeta represents the metadata for a chapter, read directly from the `chapter.yml` in the
 // directory represented by the node (fields are exported to support YAML unmarshalling)
 type chapterMeta struct {
-	Title    string `yaml:"title"`
-	Numbered *bool  `yaml:"numbered"`
+	Title               string `yaml:"title"`
+	Subtitle            string `yaml:"subtitle"`
+	Numbered            *bool  `yaml:"numbered"`
+	Number              *int   `yaml:"number"`
+	Epigraph            string `yaml:"epigraph"`
+	EpigraphAttribution string `yaml:"epigraphAttribution"`
+}
+
+// partMeta represents the metadata for a part, read from the `part.yml` in the directory
+// represented by the node (fields are exported to support YAML unmarshalling)
+type partMeta struct {
+	Title string `yaml:"title"`
+}
+
+// sceneMeta represents the metadata for a scene, read from the optional YAML front matter at the
+// top of the scene file (fields are exported to support YAML unmarshalling)
+type sceneMeta struct {
+	Status   string   `yaml:"status"`
+	POV      string   `yaml:"pov"`
+	Synopsis string   `yaml:"synopsis"`
+	Tags     []string `yaml:"tags"`
+	Date     string   `yaml:"date"`
+	Exclude  bool     `yaml:"exclude"`
 }
 
 var metaFilenames = map[string]bool{
 	"chapter.yml": true,
+	"part.yml":    true,
 }
 
 type FileSystemObject interface {
@@ -41,18 +66,87 @@ type FileSystemObject interface {
 	PrettyFileName() string
 }
 
+// addChapterMeta reads the `chapter.yml` and `part.yml` markers in the node's directory, if it
+// has them
 func (n *node) addChapterMeta(path string) (err error) {
-	content, err := os.ReadFile(filepath.Join(path, "chapter.yml"))
-	if errors.Is(err, fs.ErrNotExist) {
-		return nil
-	} else if err != nil {
+	for _, marker := range []struct {
+		name string
+		meta any
+	}{{"chapter.yml", &n.chapterMeta}, {"part.yml", &n.partMeta}} {
+		var content []byte
+		content, err = os.ReadFile(filepath.Join(path, marker.name))
+		if errors.Is(err, fs.ErrNotExist) {
+			err = nil
+			continue
+		} else if err != nil {
+			return
+		}
+
+		if err = yaml.Unmarshal(content, marker.meta); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// frontMatterDelimiter opens and closes the front matter block at the top of a scene file
+const frontMatterDelimiter = "---"
+
+// splitFrontMatter separates the YAML front matter (if any) from the rest of a scene file; the
+// front matter must start on the first line and end with a line that is just `---` or `...`
+func splitFrontMatter(content []byte) (frontMatter []byte, body []byte) {
+	lines := strings.SplitAfter(string(content), "\n")
+	if strings.TrimRight(lines[0], "\r\n") != frontMatterDelimiter {
+		return nil, content
+	}
+
+	offset := len(lines[0])
+	for _, line := range lines[1:] {
+		trimmed := strings.TrimRight(line, "\r\n")
+		if trimmed == frontMatterDelimiter || trimmed == "..." {
+			return content[len(lines[0]):offset], content[offset+len(line):]
+		}
+		offset += len(line)
+	}
+
+	// never closed, so this is just content that happens to start with a horizontal rule
+	return nil, content
+}
+
+func (n *node) addSceneMeta() (err error) {
+	// only read the whole file if it starts with front matter; content is otherwise loaded lazily
+	file, err := os.Open(n.path)
+	if err != nil {
 		return
 	}
+	defer func() { _ = file.Close() }()
 
-	if err = yaml.Unmarshal(content, &n.chapterMeta); err != nil {
+	start := make([]byte, len(frontMatterDelimiter))
+	if _, err = io.ReadFull(file, start); err != nil {
+		// files shorter than the delimiter have no front matter
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			err = nil
+		}
+		return
+	}
+	if string(start) != frontMatterDelimiter {
 		return
 	}
 
+	content, err := os.ReadFile(n.path)
+	if err != nil {
+		return
+	}
+	frontMatter, _ := splitFrontMatter(content)
+	if frontMatter == nil {
+		return
+	}
+
+	if err = yaml.Unmarshal(frontMatter, &n.sceneMeta); err != nil {
+		return fmt.Errorf("invalid front matter in %s: %w", n.path, err)
+	}
+
 	return
 }
 
@@ -94,12 +188,15 @@ func (n *node) setFileNumber() {
 	return
 }
 
+// loadContent reads the scene file, minus its front matter
 func (n *node) loadContent() (err error) {
 	if n.content == nil {
-		n.content, err = os.ReadFile(n.path)
+		var content []byte
+		content, err = os.ReadFile(n.path)
 		if err != nil {
 			return err
 		}
+		_, n.content = splitFrontMatter(content)
 	}
 	return
 }
@@ -124,10 +221,15 @@ func (n *node) walk(fn func(*node)) {
 	}
 }
 
-func (n *node) folders() (folders []Folder) {
+// folders returns the child folders of this node; when the manuscript is filtered, folders with no
+// included scenes are left out
+func (n *node) folders(m *manuscript, parent *folder) (folders []Folder) {
 	for _, child := range n.children {
 		if child.isDir {
-			folders = append(folders, &folder{node: child})
+			f := &folder{node: child, manuscript: m, parentFolder: parent}
+			if m.keep == nil || len(f.AllScenes()) > 0 {
+				folders = append(folders, f)
+			}
 		}
 	}
 	return
@@ -155,5 +257,6 @@ func newDirNode(path string) (n *node, err error) {
 func newFileNode(path string) (n *node, err error) {
 	n = &node{isDir: false, path: path}
 	n.setFileNumber()
+	err = n.addSceneMeta()
 	return
 }
@@ -0,0 +1,22 @@
+package ms
+
+import "testing"
+
+func TestSplitFrontMatter(t *testing.T) {
+	for _, tc := range []struct {
+		content     string
+		frontMatter string
+		body        string
+	}{
+		{"just text\n", "", "just text\n"},
+		{"---\nstatus: draft\n---\nbody\n", "status: draft\n", "body\n"},
+		{"---\r\npov: Anna\r\n...\r\nbody", "pov: Anna\r\n", "body"},
+		{"---\n\nnot closed\n", "", "---\n\nnot closed\n"},
+	} {
+		frontMatter, body := splitFrontMatter([]byte(tc.content))
+		if string(frontMatter) != tc.frontMatter || string(body) != tc.body {
+			t.Errorf("expected %q to split into %q and %q, got %q and %q",
+				tc.content, tc.frontMatter, tc.body, frontMatter, body)
+		}
+	}
+}
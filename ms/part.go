@@ -0,0 +1,58 @@
+package ms
+
+import "fmt"
+
+type part struct {
+	node       *node
+	manuscript *manuscript
+	number     int
+}
+
+// Part is a division of the book above chapters, like "Part One: The Crossing"; it starts with
+// the chapter in the folder that has its `part.yml` and runs to the next part
+type Part interface {
+	fmt.Stringer
+	Chapters() []Chapter
+	Title() string
+	Number() int
+	Path() string
+}
+
+func (p *part) String() string {
+	return fmt.Sprintf("Part{%s} of %s", p.Title(), p.manuscript)
+}
+
+// Chapters returns the chapters in this part. Like chapter scenes, parts are waypoints in the walk
+// of the manuscript, so this gathers the chapters between this part's node and the next part's.
+func (p *part) Chapters() (chapters []Chapter) {
+	inPart := map[string]bool{}
+	capturing := false
+	p.manuscript.node.walk(func(node *node) {
+		if node.partMeta != nil {
+			capturing = node.partMeta == p.node.partMeta
+		}
+		if capturing && node.chapterMeta != nil {
+			inPart[node.path] = true
+		}
+	})
+
+	// use the manuscript's chapters so they keep their numbers (and any filter applies)
+	for _, chapter := range p.manuscript.Chapters() {
+		if inPart[chapter.Path()] {
+			chapters = append(chapters, chapter)
+		}
+	}
+	return
+}
+
+func (p *part) Title() string {
+	return p.node.partMeta.Title
+}
+
+func (p *part) Number() int {
+	return p.number
+}
+
+func (p *part) Path() string {
+	return p.node.path
+}
@@ -0,0 +1,75 @@
+package ms
+
+import "fmt"
+
+// profileMeta represents a named compile profile from the `profiles` section of `otis.yml`
+// (fields are exported to support YAML unmarshalling)
+type profileMeta struct {
+	Format         *string  `yaml:"format"`
+	Tag            *string  `yaml:"tag"`
+	Chapters       *string  `yaml:"chapters"`
+	Folders        []string `yaml:"folders"`
+	SceneBreak     *string  `yaml:"sceneBreak"`
+	ChapterHeading *string  `yaml:"chapterHeading"`
+	TitlePage      *bool    `yaml:"titlePage"`
+	WordCount      *string  `yaml:"wordCount"`
+}
+
+type profile struct {
+	name string
+	meta profileMeta
+}
+
+// Profile is a named set of compile settings; settings the profile doesn't specify are nil
+type Profile interface {
+	fmt.Stringer
+	Name() string
+	Format() *string
+	Tag() *string
+	Chapters() *string
+	Folders() []string
+	SceneBreak() *string
+	ChapterHeading() *string
+	TitlePage() *bool
+	WordCount() *string
+}
+
+func (p *profile) String() string {
+	return fmt.Sprintf("Profile{%s}", p.name)
+}
+
+func (p *profile) Name() string {
+	return p.name
+}
+
+func (p *profile) Format() *string {
+	return p.meta.Format
+}
+
+func (p *profile) Tag() *string {
+	return p.meta.Tag
+}
+
+func (p *profile) Chapters() *string {
+	return p.meta.Chapters
+}
+
+func (p *profile) Folders() []string {
+	return p.meta.Folders
+}
+
+func (p *profile) SceneBreak() *string {
+	return p.meta.SceneBreak
+}
+
+func (p *profile) ChapterHeading() *string {
+	return p.meta.ChapterHeading
+}
+
+func (p *profile) TitlePage() *bool {
+	return p.meta.TitlePage
+}
+
+func (p *profile) WordCount() *string {
+	return p.meta.WordCount
+}
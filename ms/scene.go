@@ -15,6 +15,12 @@ type Scene interface {
 	Folder() Folder
 	Number() int
 	Text() (string, error)
+	Status() string
+	POV() string
+	Synopsis() string
+	Tags() []string
+	Date() string
+	Excluded() bool
 }
 
 func (s *scene) String() string {
@@ -42,6 +48,43 @@ func (s *scene) Text() (string, error) {
 	return text, nil
 }
 
+// meta returns the scene's front matter, or empty metadata if it has none
+func (s *scene) meta() sceneMeta {
+	if s.node.sceneMeta == nil {
+		return sceneMeta{}
+	}
+	return *s.node.sceneMeta
+}
+
+// Status returns the scene's status from its front matter (like "draft" or "final")
+func (s *scene) Status() string {
+	return s.meta().Status
+}
+
+// POV returns the point-of-view character from the scene's front matter
+func (s *scene) POV() string {
+	return s.meta().POV
+}
+
+func (s *scene) Synopsis() string {
+	return s.meta().Synopsis
+}
+
+func (s *scene) Tags() []string {
+	return s.meta().Tags
+}
+
+// Date returns the date from the scene's front matter as written; it is often a date in the
+// story rather than a calendar date, so otis doesn't interpret it
+func (s *scene) Date() string {
+	return s.meta().Date
+}
+
+// Excluded reports whether the scene's front matter marks it to be left out of compiled output
+func (s *scene) Excluded() bool {
+	return s.meta().Exclude
+}
+
 func (s *scene) PrettyFileName() string {
 	return s.node.prettyFileName()
 }
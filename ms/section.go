@@ -0,0 +1,98 @@
+package ms
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-yaml/yaml"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// sectionMeta represents the metadata for a front or back matter section, read from the optional
+// YAML front matter at the top of its file (fields are exported to support YAML unmarshalling)
+type sectionMeta struct {
+	Title string `yaml:"title"`
+}
+
+// section is a piece of front or back matter, like a dedication or "About the Author": a markdown
+// file in the `frontmatter/` or `backmatter/` folder next to `manuscript/`
+type section struct {
+	node *node
+	meta sectionMeta
+}
+
+// Section is a piece of front or back matter. Sections are compiled before or after the chapters,
+// without chapter numbers, and they don't count toward the manuscript's word count.
+type Section interface {
+	fmt.Stringer
+	Path() string
+	PrettyFileName() string
+	Title() string
+	Text() (string, error)
+}
+
+const (
+	frontMatterDir = "frontmatter"
+	backMatterDir  = "backmatter"
+)
+
+func (s *section) String() string {
+	return fmt.Sprintf("Section{%s}", s.node.path)
+}
+
+func (s *section) Path() string {
+	return s.node.path
+}
+
+func (s *section) PrettyFileName() string {
+	return s.node.prettyFileName()
+}
+
+// Title returns the heading of the section from its front matter; it is empty for sections that
+// have no heading, like a dedication
+func (s *section) Title() string {
+	return s.meta.Title
+}
+
+func (s *section) Text() (string, error) {
+	if err := s.node.loadContent(); err != nil {
+		return "", err
+	}
+	return string(s.node.content), nil
+}
+
+// loadSections loads the sections in a front or back matter folder, in file name order; the folder
+// is optional
+func loadSections(dir string) (sections []Section, err error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".md" {
+			return nil, errors.New(fmt.Sprintf("unexpected file in %s: %s", filepath.Base(dir), path))
+		}
+
+		s := &section{node: &node{path: path}}
+		var content []byte
+		if content, err = os.ReadFile(path); err != nil {
+			return
+		}
+		if frontMatter, _ := splitFrontMatter(content); frontMatter != nil {
+			if err = yaml.Unmarshal(frontMatter, &s.meta); err != nil {
+				return nil, fmt.Errorf("invalid front matter in %s: %w", path, err)
+			}
+		}
+		sections = append(sections, s)
+	}
+	return
+}
@@ -0,0 +1,126 @@
+package ms
+
+import (
+	"gwcoffey/otis/oerr"
+	"path/filepath"
+	"strings"
+)
+
+// InScenes returns a scene filter that includes exactly the given scenes
+func InScenes(scenes []Scene) func(Scene) bool {
+	paths := map[string]bool{}
+	for _, s := range scenes {
+		paths[s.Path()] = true
+	}
+	return func(s Scene) bool {
+		return paths[s.Path()]
+	}
+}
+
+// NotExcluded is a scene filter that leaves out scenes whose front matter excludes them
+func NotExcluded(s Scene) bool {
+	return !s.Excluded()
+}
+
+// WithoutStatus returns a scene filter that leaves out scenes with any of the given statuses
+func WithoutStatus(statuses []string) func(Scene) bool {
+	return func(s Scene) bool {
+		for _, status := range statuses {
+			if strings.EqualFold(s.Status(), status) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// sceneFields are the front matter fields a condition can test; each returns every value the
+// scene has for the field
+var sceneFields = map[string]func(Scene) []string{
+	"status": func(s Scene) []string { return []string{s.Status()} },
+	"pov":    func(s Scene) []string { return []string{s.POV()} },
+	"tag":    func(s Scene) []string { return s.Tags() },
+	"date":   func(s Scene) []string { return []string{s.Date()} },
+}
+
+// Where returns a scene filter for a condition like `pov=Anna` or `status!=draft`; values are
+// compared without regard to case, and `tag=x` matches any scene with x among its tags
+func Where(condition string) (func(Scene) bool, error) {
+	field, value, found := strings.Cut(condition, "=")
+	negate := strings.HasSuffix(field, "!")
+	field = strings.ToLower(strings.TrimSpace(strings.TrimSuffix(field, "!")))
+	values, known := sceneFields[field]
+	if !found || !known {
+		return nil, oerr.InvalidCondition(condition)
+	}
+	value = strings.TrimSpace(value)
+
+	return func(s Scene) bool {
+		for _, v := range values(s) {
+			if strings.EqualFold(v, value) {
+				return !negate
+			}
+		}
+		return negate
+	}, nil
+}
+
+// Select narrows the manuscript to the scenes that meet every condition (see Where) and don't
+// have any of the excluded statuses
+func Select(m Manuscript, conditions []string, excludeStatuses []string) (Manuscript, error) {
+	for _, condition := range conditions {
+		keep, err := Where(condition)
+		if err != nil {
+			return nil, err
+		}
+		m = m.Filter(keep)
+	}
+	if len(excludeStatuses) > 0 {
+		m = m.Filter(WithoutStatus(excludeStatuses))
+	}
+	return m, nil
+}
+
+// InChapters returns a scene filter that includes the scenes of the given chapters
+func InChapters(chapters []Chapter) func(Scene) bool {
+	var scenes []Scene
+	for _, c := range chapters {
+		scenes = append(scenes, c.Scenes()...)
+	}
+	return InScenes(scenes)
+}
+
+// InFolders returns a scene filter that includes every scene in the given folders (at any depth)
+func InFolders(folders []Folder) func(Scene) bool {
+	var scenes []Scene
+	for _, f := range folders {
+		scenes = append(scenes, f.AllScenes()...)
+	}
+	return InScenes(scenes)
+}
+
+// FindFolder returns the folder in the manuscript at the given path
+func FindFolder(m Manuscript, path string) (Folder, error) {
+	target, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var search func(folders []Folder) Folder
+	search = func(folders []Folder) Folder {
+		for _, f := range folders {
+			if fpath, ferr := filepath.Abs(f.Path()); ferr == nil && fpath == target {
+				return f
+			}
+			if found := search(f.Folders()); found != nil {
+				return found
+			}
+		}
+		return nil
+	}
+
+	if found := search(m.Folders()); found != nil {
+		return found, nil
+	}
+	return nil, oerr.FolderNotFound(path)
+}
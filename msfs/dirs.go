@@ -4,8 +4,15 @@ import (
 	"gwcoffey/otis/work"
 	"os"
 	"path/filepath"
+	"time"
 )
 
+// ArchiveDir returns the path to the archive directory of a given manuscript, where removed
+// scenes and folders can be kept out of the way
+func ArchiveDir(msPath string) string {
+	return filepath.Join(msPath, "archive")
+}
+
 // TmpDir returns the path to the temporary build directory of a given manuscript, creating
 // it if necessary
 func TmpDir(msPath string) (path string, err error) {
@@ -24,6 +31,16 @@ func DistDir(msPath string) (path string, err error) {
 	return
 }
 
+// Journal returns the journal of structural changes made to a given manuscript, which is kept in
+// its temporary build directory
+func Journal(msPath string) (*work.Journal, error) {
+	dir, err := TmpDir(msPath)
+	if err != nil {
+		return nil, err
+	}
+	return work.NewJournal(dir), nil
+}
+
 func LastIndex(dir string) (int, error) {
 	entries, err := os.ReadDir(dir)
 	if err != nil {
@@ -53,8 +70,15 @@ func NextIndex(dir string) (int, error) {
 }
 
 // MakeRoom makes room in the given directory for a new item with the given index by moving
-// existing items with later indices up one spot
-func MakeRoom(path string, index int) (workList work.List, err error) {
+// existing items with later indices up one spot (starting from the last, so nothing collides);
+// numbers are padded to the given width
+func MakeRoom(path string, index int, width int) (workList work.List, err error) {
+	return MakeRoomFor(path, index, 1, width)
+}
+
+// MakeRoomFor is like MakeRoom but makes room for count new items; files with no number (like
+// chapter.yml) are left where they are
+func MakeRoomFor(path string, index int, count int, width int) (workList work.List, err error) {
 	entries, err := os.ReadDir(path)
 	if err != nil {
 		return
@@ -62,16 +86,75 @@ func MakeRoom(path string, index int) (workList work.List, err error) {
 
 	workList = work.List{}
 
-	for _, entry := range entries {
-		var n int
-		n, err = FileNumber(entry.Name())
-		if err != nil {
-			return
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		n, nerr := FileNumber(entry.Name())
+		if nerr != nil {
+			continue
 		}
 		if n >= index {
-			workList = work.AppendRename(workList, filepath.Join(path, entry.Name()), RenumberFilename(entry.Name(), n+1))
+			workList = work.AppendRename(workList, filepath.Join(path, entry.Name()), RenumberFilename(entry.Name(), n+count, width))
 		}
 	}
 
 	return
 }
+
+// TrashPath returns where to keep a deleted item so `otis undo` can still restore it
+func TrashPath(msPath string, path string) (string, error) {
+	tmp, err := TmpDir(msPath)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(tmp, "trash", time.Now().Format("20060102-150405.000000"), filepath.Base(path)), nil
+}
+
+// CloseHole renumbers the items after the given path in its directory down one spot, to close
+// the hole it leaves when it is moved or removed (which must come earlier in the list)
+func CloseHole(workList work.List, path string, width int) (work.List, error) {
+	return CloseHoles(workList, filepath.Dir(path), []string{path}, width)
+}
+
+// CloseHoles is like CloseHole for several items removed from the same directory: each remaining
+// item moves down one spot for every removed item before it
+func CloseHoles(workList work.List, dir string, paths []string, width int) (work.List, error) {
+	var removed []int
+	for _, path := range paths {
+		number, err := FileNumber(path)
+		if err != nil {
+			continue // nothing to do, but not really an error
+		}
+		removed = append(removed, number)
+	}
+	if len(removed) == 0 {
+		return workList, nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return workList, err
+	}
+
+	gone := map[string]bool{}
+	for _, path := range paths {
+		gone[filepath.Base(path)] = true
+	}
+
+	for _, entry := range entries {
+		num, nerr := FileNumber(entry.Name())
+		if nerr != nil || gone[entry.Name()] {
+			continue // just ignore files with no number
+		}
+		shift := 0
+		for _, number := range removed {
+			if number < num {
+				shift++
+			}
+		}
+		if shift > 0 {
+			workList = work.AppendRename(workList, filepath.Join(dir, entry.Name()), RenumberFilename(entry.Name(), num-shift, width))
+		}
+	}
+
+	return workList, nil
+}
@@ -0,0 +1,44 @@
+package msfs
+
+import (
+	"gwcoffey/otis/work"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestCloseHoles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"00-a.md", "01-b.md", "02-c.md", "03-d.md", "04-e", "05-f.md", "chapter.yml"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// each item moves down once for every removed item before it
+	list, err := CloseHoles(work.List{}, dir, []string{filepath.Join(dir, "01-b.md"), filepath.Join(dir, "03-d.md")}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := work.AppendRename(work.List{}, filepath.Join(dir, "02-c.md"), "01-c.md")
+	expected = work.AppendRename(expected, filepath.Join(dir, "04-e"), "02-e")
+	expected = work.AppendRename(expected, filepath.Join(dir, "05-f.md"), "03-f.md")
+	if !slices.Equal(list, expected) {
+		t.Errorf("expected\n%sgot\n%s", work.PrintableString(expected), work.PrintableString(list))
+	}
+
+	// the width applies to the new numbers
+	list, err = CloseHole(work.List{}, filepath.Join(dir, "04-e"), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(list, work.AppendRename(work.List{}, filepath.Join(dir, "05-f.md"), "004-f.md")) {
+		t.Errorf("expected 05-f.md to become 004-f.md, got\n%s", work.PrintableString(list))
+	}
+
+	// nothing moves for an unnumbered item
+	if list, err = CloseHole(work.List{}, filepath.Join(dir, "chapter.yml"), 2); err != nil || len(list) != 0 {
+		t.Errorf("expected nothing to move, got %v %v", list, err)
+	}
+}
@@ -25,24 +25,23 @@ func ReadFileWithImport(path string) ([]byte, error) {
 		return nil, err
 	}
 
-	scanner := bufio.NewScanner(bytes.NewReader(data))
-	if !scanner.Scan() {
-		return data, nil
-	}
-
-	firstLine := scanner.Text()
-	if !strings.HasPrefix(firstLine, "#import ") {
+	importPath := ImportPath(path, data)
+	if importPath == "" {
 		return data, nil
 	}
 
-	importPath := strings.TrimSpace(strings.TrimPrefix(firstLine, "#import "))
-	importData, err := os.ReadFile(filepath.Join(filepath.Base(path), importPath))
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Scan() // skip the #import line
+	importData, err := os.ReadFile(importPath)
 	if err != nil {
 		return nil, err
 	}
 
 	var result bytes.Buffer
 	result.Write(importData)
+	if len(importData) > 0 && importData[len(importData)-1] != '\n' {
+		result.WriteByte('\n')
+	}
 	for scanner.Scan() {
 		result.Write(scanner.Bytes())
 		result.WriteByte('\n')
@@ -51,6 +50,20 @@ func ReadFileWithImport(path string) ([]byte, error) {
 	return result.Bytes(), scanner.Err()
 }
 
+// ImportPath returns the path of the file imported by the `#import` line at the start of the
+// file's data (relative paths are relative to the file's folder), or "" if there isn't one
+func ImportPath(path string, data []byte) string {
+	firstLine, _, _ := strings.Cut(string(data), "\n")
+	if !strings.HasPrefix(firstLine, "#import ") {
+		return ""
+	}
+	importPath := strings.TrimSpace(strings.TrimPrefix(firstLine, "#import "))
+	if filepath.IsAbs(importPath) {
+		return importPath
+	}
+	return filepath.Join(filepath.Dir(path), importPath)
+}
+
 var numberPrefixPattern = regexp.MustCompile(`^\d+`)
 
 // FileNumber returns the number of a file following otis naming convention of `##-foo`
@@ -74,19 +87,21 @@ func FileNameWithoutNumber(path string) (name string, err error) {
 	return
 }
 
-func RenumberFilename(name string, newNum int) string {
+// RenumberFilename replaces the number prefix of a file name (or adds one), padding the number with
+// zeros to the given width
+func RenumberFilename(name string, newNum int, width int) string {
 	newName, err := FileNameWithoutNumber(name)
 	if err != nil {
 		// ignore unnumbered file error and just number it
 		newName = name
 	}
-	return fmt.Sprintf("%02d-%s", newNum, newName)
+	return fmt.Sprintf("%0*d-%s", width, newNum, newName)
 }
 
-func MakeFilename(name string, num int) string {
-	return fmt.Sprintf("%02d-%s.md", num, text.ToKebab(name))
+func MakeFilename(name string, num int, width int) string {
+	return fmt.Sprintf("%0*d-%s.md", width, num, text.ToKebab(name))
 }
 
-func MakeDirname(name string, num int) string {
-	return fmt.Sprintf("%02d-%s", num, text.ToKebab(name))
+func MakeDirname(name string, num int, width int) string {
+	return fmt.Sprintf("%0*d-%s", width, num, text.ToKebab(name))
 }
@@ -16,6 +16,20 @@ const (
 	projectNotFound ErrorCode = iota + 1
 	alreadyAProject
 	pathOrAtRequired
+	profileNotFound
+	invalidCompileOption
+	folderNotFound
+	invalidCondition
+	notAGitRepo
+	invalidPlan
+	nothingToUndo
+	splitPointRequired
+	noSplitPoint
+	cantJoin
+	manuscriptHasProblems
+	notAChapter
+	alreadyAChapter
+	chapterSubcommandRequired
 )
 
 func ProjectNotFound() *OtisError {
@@ -42,6 +56,65 @@ func MissingFileNumber(path string) *OtisError {
 	return &OtisError{Code: pathOrAtRequired, Message: fmt.Sprintf("path %s is missing required file number prefix", path)}
 }
 
+func ProfileNotFound(name string) *OtisError {
+	return &OtisError{Code: profileNotFound, Message: fmt.Sprintf("there is no compile profile named %s in otis.yml", name)}
+}
+
+func InvalidCompileOption(option string, value string) *OtisError {
+	return &OtisError{Code: invalidCompileOption, Message: fmt.Sprintf("%s is not a valid value for %s", value, option)}
+}
+
+func FolderNotFound(path string) *OtisError {
+	return &OtisError{Code: folderNotFound, Message: fmt.Sprintf("%s is not a folder in the manuscript", path)}
+}
+
+func InvalidCondition(condition string) *OtisError {
+	return &OtisError{Code: invalidCondition, Message: fmt.Sprintf("%s is not a valid condition (use field=value or field!=value, where field is status, pov, tag, or date)", condition)}
+}
+
+func NotAGitRepo(path string) *OtisError {
+	return &OtisError{Code: notAGitRepo, Message: fmt.Sprintf("%s is not in a git repository", path)}
+}
+
+func InvalidPlan(reason string) *OtisError {
+	return &OtisError{Code: invalidPlan, Message: fmt.Sprintf("nothing was changed because %s", reason)}
+}
+
+func NothingToUndo(steps int, available int) *OtisError {
+	return &OtisError{Code: nothingToUndo, Message: fmt.Sprintf("can't undo %d operations because there are %d in the journal", steps, available)}
+}
+
+func SplitPointRequired() *OtisError {
+	return &OtisError{Code: splitPointRequired, Message: "exactly one of --at-line, --at-marker, or --all-markers must be specified"}
+}
+
+func NoSplitPoint(path string, reason string) *OtisError {
+	return &OtisError{Code: noSplitPoint, Message: fmt.Sprintf("can't split %s because %s", path, reason)}
+}
+
+func CantJoin(reason string) *OtisError {
+	return &OtisError{Code: cantJoin, Message: fmt.Sprintf("can't join because %s", reason)}
+}
+
+func ManuscriptHasProblems(count int) *OtisError {
+	if count == 1 {
+		return &OtisError{Code: manuscriptHasProblems, Message: "found 1 problem"}
+	}
+	return &OtisError{Code: manuscriptHasProblems, Message: fmt.Sprintf("found %d problems", count)}
+}
+
+func NotAChapter(path string) *OtisError {
+	return &OtisError{Code: notAChapter, Message: fmt.Sprintf("%s does not start a chapter (it has no chapter.yml)", path)}
+}
+
+func AlreadyAChapter(path string) *OtisError {
+	return &OtisError{Code: alreadyAChapter, Message: fmt.Sprintf("%s already starts a chapter", path)}
+}
+
+func ChapterSubcommandRequired() *OtisError {
+	return &OtisError{Code: chapterSubcommandRequired, Message: "one of add, rm, mv, or ls must be specified"}
+}
+
 func (e *OtisError) Error() string {
 	return e.Message
 }
@@ -4,6 +4,7 @@ import (
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -21,3 +22,60 @@ func ToKebab(str string) (result string) {
 	result = strings.Trim(result, "-")
 	return
 }
+
+var ones = []string{"Zero", "One", "Two", "Three", "Four", "Five", "Six", "Seven", "Eight", "Nine", "Ten",
+	"Eleven", "Twelve", "Thirteen", "Fourteen", "Fifteen", "Sixteen", "Seventeen", "Eighteen", "Nineteen"}
+
+var tens = []string{"", "", "Twenty", "Thirty", "Forty", "Fifty", "Sixty", "Seventy", "Eighty", "Ninety"}
+
+// NumberToWords spells out a non-negative number in title case, the way it appears in a heading
+// like "Part Twenty-One"
+func NumberToWords(n int) string {
+	switch {
+	case n < 0:
+		return "Minus " + NumberToWords(-n)
+	case n < 20:
+		return ones[n]
+	case n < 100:
+		if n%10 == 0 {
+			return tens[n/10]
+		}
+		return tens[n/10] + "-" + ones[n%10]
+	}
+
+	for _, scale := range []struct {
+		size int
+		name string
+	}{{1000000, "Million"}, {1000, "Thousand"}, {100, "Hundred"}} {
+		if n >= scale.size {
+			words := NumberToWords(n/scale.size) + " " + scale.name
+			if n%scale.size > 0 {
+				words += " " + NumberToWords(n%scale.size)
+			}
+			return words
+		}
+	}
+	return ""
+}
+
+var romanNumerals = []struct {
+	value   int
+	numeral string
+}{{1000, "M"}, {900, "CM"}, {500, "D"}, {400, "CD"}, {100, "C"}, {90, "XC"}, {50, "L"}, {40, "XL"},
+	{10, "X"}, {9, "IX"}, {5, "V"}, {4, "IV"}, {1, "I"}}
+
+// ToRoman writes a positive number as an uppercase roman numeral, like "XII"; there is no roman
+// numeral for zero or negative numbers, so they are written with digits
+func ToRoman(n int) string {
+	if n <= 0 {
+		return strconv.Itoa(n)
+	}
+	var result strings.Builder
+	for _, r := range romanNumerals {
+		for n >= r.value {
+			result.WriteString(r.numeral)
+			n -= r.value
+		}
+	}
+	return result.String()
+}
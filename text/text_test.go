@@ -23,3 +23,35 @@ func TestSentenceToKebab(t *testing.T) {
 	}
 
 }
+
+func TestNumberToWords(t *testing.T) {
+	for n, expected := range map[int]string{
+		0:       "Zero",
+		7:       "Seven",
+		12:      "Twelve",
+		40:      "Forty",
+		21:      "Twenty-One",
+		105:     "One Hundred Five",
+		1999:    "One Thousand Nine Hundred Ninety-Nine",
+		2000000: "Two Million",
+	} {
+		if actual := NumberToWords(n); expected != actual {
+			t.Errorf("expected %v but got %v", expected, actual)
+		}
+	}
+}
+
+func TestToRoman(t *testing.T) {
+	for n, expected := range map[int]string{
+		0:    "0",
+		1:    "I",
+		4:    "IV",
+		12:   "XII",
+		49:   "XLIX",
+		1994: "MCMXCIV",
+	} {
+		if actual := ToRoman(n); expected != actual {
+			t.Errorf("expected %v but got %v", expected, actual)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package work
+
+import (
+	"errors"
+	"fmt"
+	"gwcoffey/otis/oerr"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// simulation tracks how a list would change the filesystem without touching it, so a list can be
+// checked before any of it runs
+type simulation struct {
+	// changed maps each path the list has touched so far to where its content came from: a real
+	// path for moved items, "" for new items, or nil if it has been moved away
+	changed map[string]*string
+}
+
+// origin returns the real path whose content is at p once the simulated changes are applied;
+// isNew is set for items the list creates, and ok is false if nothing is at p
+func (s *simulation) origin(p string) (real string, isNew bool, ok bool) {
+	for dir := p; ; dir = filepath.Dir(dir) {
+		if source, changed := s.changed[dir]; changed {
+			switch {
+			case source == nil:
+				return "", false, false
+			case *source == "":
+				// children of new directories only exist if the list adds them
+				return "", true, dir == p
+			default:
+				return *source + strings.TrimPrefix(p, dir), false, true
+			}
+		}
+		if parent := filepath.Dir(dir); parent == dir {
+			break
+		}
+	}
+	return p, false, true
+}
+
+// exists reports whether something is at p once the simulated changes are applied
+func (s *simulation) exists(p string) bool {
+	real, isNew, ok := s.origin(p)
+	if !ok || isNew {
+		return ok
+	}
+	_, err := os.Lstat(real)
+	return !errors.Is(err, fs.ErrNotExist)
+}
+
+func (s *simulation) move(from string, to string) {
+	real, isNew, _ := s.origin(from)
+	if isNew {
+		real = ""
+	}
+
+	// anything the list already changed inside a moved directory moves with it
+	prefix := from + string(filepath.Separator)
+	for p, source := range s.changed {
+		if strings.HasPrefix(p, prefix) {
+			delete(s.changed, p)
+			s.changed[to+strings.TrimPrefix(p, from)] = source
+		}
+	}
+
+	s.changed[to] = &real
+	s.changed[from] = nil
+}
+
+func (s *simulation) add(p string) {
+	created := ""
+	s.changed[p] = &created
+}
+
+// destination returns the path a work item creates or moves something to
+func (w Work) destination() string {
+	switch w.action {
+	case rename:
+		return filepath.Join(filepath.Dir(w.path), w.arg)
+	case move, remove:
+		return w.arg
+	default:
+		return w.path
+	}
+}
+
+// Check makes sure every item in the list can run, in order: everything renamed or moved must
+// exist, and nothing may be created, renamed, or moved on top of something that already exists
+func Check(items List) error {
+	sim := simulation{changed: map[string]*string{}}
+	for _, w := range items {
+		to := filepath.Clean(w.destination())
+		if w.action == rename || w.action == move || w.action == remove {
+			from := filepath.Clean(w.path)
+			if !sim.exists(from) {
+				return oerr.InvalidPlan(fmt.Sprintf("%s does not exist", w.path))
+			}
+			if sim.exists(to) {
+				return oerr.InvalidPlan(fmt.Sprintf("%s already exists", to))
+			}
+			sim.move(from, to)
+		} else {
+			if sim.exists(to) {
+				return oerr.InvalidPlan(fmt.Sprintf("%s already exists", to))
+			}
+			if !sim.exists(filepath.Dir(to)) {
+				return oerr.InvalidPlan(fmt.Sprintf("%s does not exist", filepath.Dir(to)))
+			}
+			sim.add(to)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+package work
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// journalFilename is the journal file in the project's build directory; each line is an Entry
+const journalFilename = "journal.jsonl"
+
+// Journal is a persistent record of the lists that have been executed in a project, so they can
+// be undone
+type Journal struct {
+	dir string
+}
+
+// Entry is one executed list
+type Entry struct {
+	Time    time.Time `json:"time"`
+	Command string    `json:"command"`
+	Items   List      `json:"items"`
+}
+
+var actionNames = map[action]string{
+	rename:  "rename",
+	addFile: "addFile",
+	addDir:  "addDir",
+	move:    "move",
+	remove:  "remove",
+}
+
+// workJson is the JSON form of a Work item; renames have a new name, moves and removals have a
+// destination path, and new files may have content
+type workJson struct {
+	Action  string `json:"action"`
+	Path    string `json:"path"`
+	Name    string `json:"name,omitempty"`
+	To      string `json:"to,omitempty"`
+	Content string `json:"content,omitempty"`
+}
+
+func (w Work) MarshalJSON() ([]byte, error) {
+	j := workJson{Action: actionNames[w.action], Path: w.path}
+	switch w.action {
+	case rename:
+		j.Name = w.arg
+	case addFile:
+		j.Content = w.arg
+	default:
+		j.To = w.arg
+	}
+	return json.Marshal(j)
+}
+
+func (w *Work) UnmarshalJSON(data []byte) error {
+	var j workJson
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	for a, name := range actionNames {
+		if name == j.Action {
+			w.action = a
+			w.path = j.Path
+			switch a {
+			case rename:
+				w.arg = j.Name
+			case addFile:
+				w.arg = j.Content
+			default:
+				w.arg = j.To
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown action %q", j.Action)
+}
+
+// NewJournal returns the journal kept in the given build directory
+func NewJournal(buildDir string) *Journal {
+	return &Journal{dir: buildDir}
+}
+
+func (j *Journal) path() string {
+	return filepath.Join(j.dir, journalFilename)
+}
+
+// absolute returns the items with absolute paths, so they still make sense when run from
+// another directory
+func absolute(items List) (result List, err error) {
+	for _, w := range items {
+		if w.path, err = filepath.Abs(w.path); err != nil {
+			return
+		}
+		if w.action == move || w.action == remove {
+			if w.arg, err = filepath.Abs(w.arg); err != nil {
+				return
+			}
+		}
+		result = append(result, w)
+	}
+	return
+}
+
+// record appends an executed list to the journal, noting the command line that produced it
+func (j *Journal) record(items List) (err error) {
+	abs, err := absolute(items)
+	if err != nil {
+		return
+	}
+	line, err := json.Marshal(Entry{
+		Time:    time.Now(),
+		Command: strings.Join(append([]string{"otis"}, os.Args[1:]...), " "),
+		Items:   abs,
+	})
+	if err != nil {
+		return
+	}
+
+	file, err := os.OpenFile(j.path(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if cerr := file.Close(); err == nil {
+			err = cerr
+		}
+	}()
+	_, err = file.Write(append(line, '\n'))
+	return
+}
+
+// Entries returns everything in the journal, oldest first
+func (j *Journal) Entries() (entries []Entry, err error) {
+	file, err := os.Open(j.path())
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return
+	}
+	defer func() { _ = file.Close() }()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var entry Entry
+		if err = json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("%s: %w", j.path(), err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// Drop removes the last count entries from the journal
+func (j *Journal) Drop(count int) (err error) {
+	entries, err := j.Entries()
+	if err != nil {
+		return
+	}
+	entries = entries[:max(0, len(entries)-count)]
+
+	var out strings.Builder
+	for _, entry := range entries {
+		var line []byte
+		if line, err = json.Marshal(entry); err != nil {
+			return
+		}
+		out.Write(line)
+		out.WriteString("\n")
+	}
+	return os.WriteFile(j.path(), []byte(out.String()), 0644)
+}
+
+// Inverse returns a list that undoes the given entries (which must be in the order they ran).
+// Items that were added are removed into the journal's trash rather than deleted outright.
+func (j *Journal) Inverse(entries []Entry) (inverse List) {
+	stamp := time.Now().Format("20060102-150405.000000")
+	for e := len(entries) - 1; e >= 0; e-- {
+		items := entries[e].Items
+		for i := len(items) - 1; i >= 0; i-- {
+			w := items[i]
+			switch w.action {
+			case rename:
+				inverse = AppendRename(inverse, w.destination(), filepath.Base(w.path))
+			case move, remove:
+				inverse = AppendMove(inverse, w.arg, w.path)
+			case addFile, addDir:
+				keep := filepath.Join(j.dir, "trash", stamp, fmt.Sprintf("%d-%d-%s", e, i, filepath.Base(w.path)))
+				inverse = AppendRemove(inverse, w.path, keep)
+			}
+		}
+	}
+	return
+}
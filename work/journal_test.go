@@ -0,0 +1,103 @@
+package work
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestWorkJsonRoundTrip(t *testing.T) {
+	list := AppendRename(List{}, "/p/01-a.md", "02-a.md")
+	list = AddFile(list, "/p/03-b.md")
+	list = AddFileWithContent(list, "/p/04-c.md", "some text\n")
+	list = AddDir(list, "/p/05-d")
+	list = AppendMove(list, "/p/06-e.md", "/q/00-e.md")
+	list = AppendRemove(list, "/p/07-f.md", "/p/.build/trash/07-f.md")
+
+	data, err := json.Marshal(list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var read List
+	if err = json.Unmarshal(data, &read); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(read, list) {
+		t.Errorf("expected %v, got %v", list, read)
+	}
+
+	if json.Unmarshal([]byte(`[{"action":"copy","path":"/p/a.md"}]`), &read) == nil {
+		t.Error("expected an unknown action to fail")
+	}
+}
+
+func TestJournalInverse(t *testing.T) {
+	dir := makeFiles(t, "01-a.md", "02-b.md")
+	build := filepath.Join(dir, ".build")
+	if err := os.Mkdir(build, 0755); err != nil {
+		t.Fatal(err)
+	}
+	journal := NewJournal(build)
+
+	first := AppendRename(List{}, filepath.Join(dir, "02-b.md"), "03-b.md")
+	first = AddFile(first, filepath.Join(dir, "02-new.md"))
+	second := AppendRemove(List{}, filepath.Join(dir, "01-a.md"), filepath.Join(build, "kept", "01-a.md"))
+	for _, list := range []List{first, second} {
+		if err := Execute(list, true, journal); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := journal.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || !slices.Equal(entries[1].Items, second) {
+		t.Fatalf("expected both lists in the journal, got %v", entries)
+	}
+
+	// undoing both puts the files back and keeps the added scene in the trash
+	if err = Execute(journal.Inverse(entries), true, nil); err != nil {
+		t.Fatal(err)
+	}
+	names, err := filepath.Glob(filepath.Join(dir, "*.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(names, []string{filepath.Join(dir, "01-a.md"), filepath.Join(dir, "02-b.md")}) {
+		t.Error("expected the files to be restored, got", names)
+	}
+	trashed, err := filepath.Glob(filepath.Join(build, "trash", "*", "*-02-new.md"))
+	if err != nil || len(trashed) != 1 {
+		t.Error("expected the added file in the trash, got", trashed, err)
+	}
+}
+
+func TestJournalDrop(t *testing.T) {
+	journal := NewJournal(t.TempDir())
+	for _, name := range []string{"a", "b", "c"} {
+		if err := journal.record(AddFile(List{}, "/p/"+name+".md")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := journal.Drop(2); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := journal.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Items[0].path != "/p/a.md" {
+		t.Errorf("expected only the oldest entry to be left, got %v", entries)
+	}
+
+	if err = journal.Drop(5); err != nil {
+		t.Fatal(err)
+	}
+	if entries, err = journal.Entries(); err != nil || len(entries) != 0 {
+		t.Errorf("expected an empty journal, got %v %v", entries, err)
+	}
+}
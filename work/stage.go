@@ -0,0 +1,135 @@
+package work
+
+import (
+	"errors"
+	"gwcoffey/otis/git"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// staging tracks where the files git knows about end up when a list runs, so the moves can be
+// staged as renames (the way `git mv` does it) instead of showing up as deleted and new files
+type staging struct {
+	top     string
+	entries []git.IndexEntry
+	// locations holds the absolute path of each entry as the list moves it
+	locations []string
+}
+
+// canonical returns the absolute path of p with symlinks resolved, even if p doesn't exist yet,
+// so it can be compared with paths git reports
+func canonical(p string) string {
+	abs, err := filepath.Abs(p)
+	if err != nil {
+		return p
+	}
+	rest := ""
+	for dir := abs; ; dir = filepath.Dir(dir) {
+		if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+			return resolved + rest
+		} else if !errors.Is(err, fs.ErrNotExist) || filepath.Dir(dir) == dir {
+			return abs
+		}
+		rest = string(filepath.Separator) + filepath.Base(dir) + rest
+	}
+}
+
+// within returns p relative to top, or false if p is outside top
+func within(top string, p string) (string, bool) {
+	rel, err := filepath.Rel(top, p)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return rel, true
+}
+
+// planStaging works out, before anything changes, which tracked files the list moves; it returns
+// nil if the items aren't in a git work tree or don't move anything git tracks
+func planStaging(items List) (*staging, error) {
+	if len(items) == 0 {
+		return nil, nil
+	}
+	// git has to run in a directory that exists
+	start := filepath.Dir(canonical(items[0].path))
+	for _, err := os.Stat(start); err != nil && filepath.Dir(start) != start; _, err = os.Stat(start) {
+		start = filepath.Dir(start)
+	}
+	if !git.IsRepo(start) {
+		return nil, nil
+	}
+	top, err := git.TopLevel(start)
+	if err != nil {
+		return nil, err
+	}
+	top = canonical(top)
+
+	var sources []string
+	for _, w := range items {
+		if w.action == rename || w.action == move || w.action == remove {
+			if rel, ok := within(top, canonical(w.path)); ok {
+				sources = append(sources, rel)
+			}
+		}
+	}
+	if len(sources) == 0 {
+		return nil, nil
+	}
+
+	entries, err := git.Tracked(top, sources)
+	if err != nil || len(entries) == 0 {
+		return nil, err
+	}
+
+	s := &staging{top: top, entries: entries}
+	for _, entry := range entries {
+		s.locations = append(s.locations, filepath.Join(top, entry.Path))
+	}
+
+	// follow each file through the list
+	for _, w := range items {
+		if w.action != rename && w.action != move && w.action != remove {
+			continue
+		}
+		from, to := canonical(w.path), canonical(w.destination())
+		for i, location := range s.locations {
+			if location == from || strings.HasPrefix(location, from+string(filepath.Separator)) {
+				s.locations[i] = to + strings.TrimPrefix(location, from)
+			}
+		}
+	}
+	return s, nil
+}
+
+// stage updates the index to match where the files ended up; files that end up outside the work
+// tree or somewhere git ignores (like a removed scene kept in the build directory) are left in the
+// index as they were, as if they had been deleted with plain rm
+func (s *staging) stage() error {
+	moved := map[int]string{}
+	var destinations []string
+	for i, entry := range s.entries {
+		rel, inside := within(s.top, s.locations[i])
+		if inside && filepath.ToSlash(rel) != entry.Path {
+			moved[i] = filepath.ToSlash(rel)
+			destinations = append(destinations, moved[i])
+		}
+	}
+	ignored, err := git.Ignored(s.top, destinations)
+	if err != nil {
+		return err
+	}
+
+	var add []git.IndexEntry
+	var remove []string
+	for i, entry := range s.entries {
+		if rel, ok := moved[i]; ok && !ignored[rel] {
+			remove = append(remove, entry.Path)
+			add = append(add, git.IndexEntry{Mode: entry.Mode, Hash: entry.Hash, Path: rel})
+		}
+	}
+	if len(add) == 0 {
+		return nil
+	}
+	return git.UpdateIndex(s.top, add, remove)
+}
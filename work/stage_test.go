@@ -0,0 +1,43 @@
+package work
+
+import (
+	"gwcoffey/otis/fixture"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunStagesRenames(t *testing.T) {
+	root := fixture.Repo(t, "scenes")
+	if err := os.WriteFile(filepath.Join(root, ".gitignore"), []byte(".build/\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	fixture.Git(t, root, "add", ".gitignore")
+	fixture.Git(t, root, "commit", "--quiet", "--message", "ignore the build directory")
+
+	// rename a tracked scene, move a folder, remove a scene into the build directory, and add a
+	// scene git doesn't know about yet
+	dir := filepath.Join(root, "manuscript")
+	list := AppendRename(List{}, filepath.Join(dir, "00-beginning", "02-calm.md"), "03-calm.md")
+	list = AppendMove(list, filepath.Join(dir, "02-end"), filepath.Join(dir, "00-beginning", "04-end"))
+	list = AppendRemove(list, filepath.Join(dir, "01-middle", "01-city.md"), filepath.Join(root, ".build", "trash", "01-city.md"))
+	list = AddFile(list, filepath.Join(dir, "00-beginning", "02-new.md"))
+	if err := run(list); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "R  manuscript/00-beginning/02-calm.md -> manuscript/00-beginning/03-calm.md\n" +
+		"R  manuscript/02-end/00-home.md -> manuscript/00-beginning/04-end/00-home.md\n" +
+		" D manuscript/01-middle/01-city.md\n" +
+		"?? manuscript/00-beginning/02-new.md\n"
+	if status := fixture.Git(t, root, "status", "--porcelain", "--untracked-files=all"); status != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, status)
+	}
+}
+
+func TestRunOutsideGit(t *testing.T) {
+	dir := makeFiles(t, "01-a.md")
+	if planned, err := planStaging(AppendRename(List{}, filepath.Join(dir, "01-a.md"), "02-a.md")); err != nil || planned != nil {
+		t.Errorf("expected nothing to stage, got %v %v", planned, err)
+	}
+}
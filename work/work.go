@@ -1,8 +1,10 @@
 package work
 
 import (
+	"encoding/json"
 	"fmt"
 	"gwcoffey/otis/cli"
+	"io"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -16,6 +18,7 @@ const (
 	addFile
 	addDir
 	move
+	remove
 )
 
 type Work struct {
@@ -36,6 +39,11 @@ func AddFile(list List, path string) List {
 	return append(list, Work{action: addFile, path: path})
 }
 
+// AddFileWithContent adds a new file containing the given text
+func AddFileWithContent(list List, path string, content string) List {
+	return append(list, Work{action: addFile, path: path, arg: content})
+}
+
 func AddDir(list List, path string) List {
 	return append(list, Work{action: addDir, path: path})
 }
@@ -44,6 +52,17 @@ func AppendMove(list List, from string, to string) List {
 	return append(list, Work{action: move, path: from, arg: to})
 }
 
+// AppendRemove removes the file or folder at path by moving it to keep, so the removal can be
+// rolled back or undone
+func AppendRemove(list List, path string, keep string) List {
+	return append(list, Work{action: remove, path: path, arg: keep})
+}
+
+// Path returns the path of the file or folder the work item renames, moves, removes, or creates
+func (w Work) Path() string {
+	return w.path
+}
+
 func PrintableString(items List) string {
 	builder := strings.Builder{}
 	for _, w := range items {
@@ -62,6 +81,9 @@ func PrintableString(items List) string {
 			builder.WriteString(manuscriptPrefixRegex.ReplaceAllString(w.path, ""))
 			builder.WriteString(" → ")
 			builder.WriteString(manuscriptPrefixRegex.ReplaceAllString(w.arg, ""))
+		case remove:
+			builder.WriteString("REMOVE ")
+			builder.WriteString(manuscriptPrefixRegex.ReplaceAllString(w.path, ""))
 		}
 		builder.WriteString("\n")
 	}
@@ -69,44 +91,138 @@ func PrintableString(items List) string {
 	return builder.String()
 }
 
-func Execute(items List, force bool) (err error) {
+// Preview checks the list and writes it to out, either as text (like the confirmation prompt
+// shows) or as JSON that `otis apply` can run later; nothing is changed
+func Preview(items List, asJson bool, out io.Writer) (err error) {
+	if err = Check(items); err != nil {
+		return
+	}
+
+	if !asJson {
+		_, err = io.WriteString(out, PrintableString(items))
+		return
+	}
+
+	plan, err := absolute(items)
+	if err != nil {
+		return
+	}
+	if plan == nil {
+		plan = List{}
+	}
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(plan)
+}
+
+// ReadPlan reads a list saved with Preview
+func ReadPlan(path string) (items List, err error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	if err = json.Unmarshal(content, &items); err != nil {
+		return nil, fmt.Errorf("%s is not a valid plan: %w", path, err)
+	}
+	return
+}
+
+// apply performs a single work item
+func apply(w Work) error {
+	switch w.action {
+	case rename, move:
+		return os.Rename(w.path, w.destination())
+	case remove:
+		if err := os.MkdirAll(filepath.Dir(w.arg), 0777); err != nil {
+			return err
+		}
+		return os.Rename(w.path, w.arg)
+	case addFile:
+		file, err := os.OpenFile(w.path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0666)
+		if err != nil {
+			return err
+		}
+		if _, err = io.WriteString(file, w.arg); err != nil {
+			_ = file.Close()
+			return err
+		}
+		return file.Close()
+	case addDir:
+		return os.Mkdir(w.path, 0777)
+	}
+	return nil
+}
+
+// revert undoes a work item that has been applied
+func revert(w Work) error {
+	switch w.action {
+	case rename, move, remove:
+		return os.Rename(w.destination(), w.path)
+	case addFile, addDir:
+		return os.Remove(w.path)
+	}
+	return nil
+}
+
+// run applies the items in order, keeping a journal of what has been done; if any item fails,
+// the journal is replayed backwards to put everything back the way it was. In a git work tree,
+// moves of tracked files are staged so git sees them as renames.
+func run(items List) (err error) {
+	staged, err := planStaging(items)
+	if err != nil {
+		return
+	}
+
+	var journal List
+	for _, w := range items {
+		if err = apply(w); err != nil {
+			break
+		}
+		journal = append(journal, w)
+	}
+	if err == nil && staged != nil {
+		err = staged.stage()
+	}
+	if err == nil {
+		return
+	}
+
+	for i := len(journal) - 1; i >= 0; i-- {
+		if rerr := revert(journal[i]); rerr != nil {
+			return fmt.Errorf("%w; then could not undo the changes already made (%s)", err, rerr)
+		}
+	}
+	return fmt.Errorf("%w (the changes already made were undone)", err)
+}
+
+// Execute checks the list and, after confirmation (unless forced), runs it; either the whole list
+// runs or nothing is changed. If there is a journal, the list is recorded in it so it can be undone.
+func Execute(items List, force bool, journal *Journal) (err error) {
+	_, err = ExecuteConfirmed(items, force, journal)
+	return
+}
+
+// ExecuteConfirmed is Execute, but also reports whether the list ran, which it doesn't if the user
+// declines the change
+func ExecuteConfirmed(items List, force bool, journal *Journal) (ran bool, err error) {
+	if err = Check(items); err != nil {
+		return
+	}
+
 	proceed := force || len(items) <= 1
 	if !proceed {
 		prompt := fmt.Sprintf("About to change:\n\n%s\nOK to proceed?", PrintableString(items))
 		proceed = cli.Confirm(prompt)
 	}
-
-	if proceed {
-		for _, workItem := range items {
-			switch workItem.action {
-			case rename:
-				err = os.Rename(workItem.path, filepath.Join(filepath.Dir(workItem.path), workItem.arg))
-				if err != nil {
-					return
-				}
-			case addFile:
-				var file *os.File
-				file, err = os.OpenFile(workItem.path, os.O_CREATE|os.O_EXCL, 0666)
-				if err != nil {
-					return
-				}
-				err = file.Close()
-				if err != nil {
-					return
-				}
-			case addDir:
-				err = os.Mkdir(workItem.path, 0777)
-				if err != nil {
-					return
-				}
-			case move:
-				err = os.Rename(workItem.path, workItem.arg)
-				if err != nil {
-					return
-				}
-			}
-		}
+	if !proceed {
+		return
 	}
 
-	return
+	if err = run(items); err != nil {
+		return
+	}
+	if journal != nil {
+		err = journal.record(items)
+	}
+	return true, err
 }
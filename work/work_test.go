@@ -0,0 +1,66 @@
+package work
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func makeFiles(t *testing.T, names ...string) string {
+	dir := t.TempDir()
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestCheckFindsCollisions(t *testing.T) {
+	dir := makeFiles(t, "01-a.md", "02-b.md")
+
+	// a rename can't land on a file that is still there
+	err := Check(AppendRename(List{}, filepath.Join(dir, "01-a.md"), "02-b.md"))
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Error("expected a collision, got", err)
+	}
+
+	// ...but it can once that file has been renamed out of the way
+	list := AppendRename(List{}, filepath.Join(dir, "02-b.md"), "03-b.md")
+	list = AppendRename(list, filepath.Join(dir, "01-a.md"), "02-b.md")
+	if err = Check(list); err != nil {
+		t.Error("expected no collision, got", err)
+	}
+
+	// sources must exist, even inside directories the list moves
+	list = AddDir(List{}, filepath.Join(dir, "new"))
+	list = AppendMove(list, filepath.Join(dir, "01-a.md"), filepath.Join(dir, "new", "01-a.md"))
+	list = AppendMove(list, filepath.Join(dir, "new"), filepath.Join(dir, "moved"))
+	if err = Check(append(list, Work{action: rename, path: filepath.Join(dir, "moved", "01-a.md"), arg: "x.md"})); err != nil {
+		t.Error("expected the moved file to be found, got", err)
+	}
+	if Check(append(list, Work{action: rename, path: filepath.Join(dir, "new", "01-a.md"), arg: "x.md"})) == nil {
+		t.Error("expected the file to be gone from its old directory")
+	}
+}
+
+func TestRunRollsBack(t *testing.T) {
+	dir := makeFiles(t, "01-a.md", "02-b.md")
+
+	// the last item fails because its source was never there
+	list := AppendRename(List{}, filepath.Join(dir, "02-b.md"), "03-b.md")
+	list = AddFile(list, filepath.Join(dir, "04-c.md"))
+	list = AppendRename(list, filepath.Join(dir, "09-z.md"), "10-z.md")
+	if run(list) == nil {
+		t.Fatal("expected an error")
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Name() != "01-a.md" || entries[1].Name() != "02-b.md" {
+		t.Error("expected the directory to be restored, got", entries)
+	}
+}